@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/alexedwards/scs/pgxstore"
@@ -14,11 +19,16 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/gregoryAlvim/gobid/internal/api"
+	"github.com/gregoryAlvim/gobid/internal/mailer"
+	"github.com/gregoryAlvim/gobid/internal/notify"
 	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/store/blobstore"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
 
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	gob.Register(uuid.UUID{})
 	if err := godotenv.Load(); err != nil {
@@ -43,31 +53,133 @@ func main() {
 		panic(err)
 	}
 
+	blobDir := os.Getenv("GOBID_BLOB_DIR")
+	if blobDir == "" {
+		blobDir = "data/blobs"
+	}
+
+	blobs, err := blobstore.NewLocalStore(blobDir)
+	if err != nil {
+		panic(err)
+	}
+
+	tokenSecret := os.Getenv("GOBID_TOKEN_SECRET")
+	if len(tokenSecret) < 32 {
+		panic("GOBID_TOKEN_SECRET must be set to at least 32 characters")
+	}
+
+	// Account emails always go somewhere: through SMTP when it is
+	// configured, otherwise to GOBID_MAIL_FILE or the server output.
+	var accountMailer mailer.Mailer = mailer.NewWriterMailer(os.Stdout)
+	if path := os.Getenv("GOBID_MAIL_FILE"); path != "" {
+		mailFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			panic(err)
+		}
+
+		defer mailFile.Close()
+		accountMailer = mailer.NewWriterMailer(mailFile)
+	}
+
+	notifiers := notify.Multi{notify.NewInboxNotifier(pool)}
+	if addr := os.Getenv("GOBID_SMTP_ADDR"); addr != "" {
+		from := os.Getenv("GOBID_SMTP_FROM")
+		if from == "" {
+			from = "gobid@localhost"
+		}
+
+		smtpMailer := mailer.NewSMTPMailer(addr, from, os.Getenv("GOBID_SMTP_USERNAME"), os.Getenv("GOBID_SMTP_PASSWORD"))
+		notifiers = append(notifiers, notify.NewEmailNotifier(smtpMailer))
+		accountMailer = smtpMailer
+	}
+
+	sessionStore := pgxstore.New(pool)
+	defer sessionStore.StopCleanup()
+
 	s := scs.New()
-	s.Store = pgxstore.New(pool)
+	s.Store = sessionStore
 	s.Lifetime = 24 * time.Hour
 	s.Cookie.HttpOnly = true
 	s.Cookie.SameSite = http.SameSiteLaxMode
 
+	appURL := os.Getenv("GOBID_APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost:3080"
+	}
+
+	trustedProxies, err := api.ParseTrustedProxies(os.Getenv("GOBID_TRUSTED_PROXIES"))
+	if err != nil {
+		panic(err)
+	}
+
+	userService := services.NewUserService(pool)
+
 	api := api.Api{
-		Router:         chi.NewMux(),
-		UserService:    services.NewUserService(pool),
-		ProductService: services.NewProductService(pool),
-		BidsService:    services.NewBidsService(pool),
-		Sessions:       s,
+		Router:                   chi.NewMux(),
+		UserService:              userService,
+		EmailVerificationService: services.NewEmailVerificationService(pool, accountMailer, []byte(tokenSecret)),
+		PasswordResetService:     services.NewPasswordResetService(pool, userService, accountMailer, appURL+"/reset-password"),
+		TwoFactorService:         services.NewTwoFactorService(pool),
+		AccessTokenService:       services.NewAccessTokenService(pool),
+		ProductService:           services.NewProductService(pool, blobs),
+		ImageService:             services.NewImageService(pool, blobs),
+		ImportService:            services.NewImportService(pool),
+		CategoryService:          services.NewCategoryService(pool),
+		BidsService:              services.NewBidsService(pool),
+		WalletService:            services.NewWalletService(pool),
+		WatchlistService:         services.NewWatchlistService(pool, notifiers),
+		NotificationService:      services.NewNotificationService(pool),
+		ChatService:              services.NewChatService(pool, strings.Split(os.Getenv("GOBID_CHAT_BLOCKED_WORDS"), ",")),
+		RetractionService:        services.NewRetractionService(pool),
+		IdempotencyService:       services.NewIdempotencyService(pool),
+		ShillService:             services.NewShillService(pool),
+		Sessions:                 s,
 		WsUpgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool { return true },
 		},
-		AuctionLobby: services.AuctionLobby{
-			Rooms: make(map[uuid.UUID]*services.AuctionRoom),
-		},
+		AuctionLobby:   services.NewAuctionLobby(),
+		TrustedProxies: trustedProxies,
 	}
 
 	api.BindRoutes()
 
-	fmt.Println("starting server on port :3080")
+	server := &http.Server{
+		Addr:    "localhost:3080",
+		Handler: api.Router,
+	}
 
-	if err := http.ListenAndServe("localhost:3080", api.Router); err != nil {
-		panic(err)
+	shutdownSignal, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go api.IdempotencyService.RunCleanup(shutdownSignal, time.Hour)
+	go api.ShillService.Run(shutdownSignal, 15*time.Minute)
+	go api.BidsService.RunFinalization(shutdownSignal, time.Minute)
+	go api.WatchlistService.RunReminders(shutdownSignal, time.Minute)
+	go api.RunAuctionScheduler(shutdownSignal, 10*time.Second)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("starting server", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	case <-shutdownSignal.Done():
+		slog.Info("shutting down server")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := api.AuctionLobby.Shutdown(shutdownCtx); err != nil {
+		slog.Error("auction rooms did not drain in time", "error", err)
+	}
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("http server did not shut down cleanly", "error", err)
 	}
 }
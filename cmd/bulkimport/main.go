@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/product"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+// bulkimport lists every row of a CSV or NDJSON file as a product of the
+// seller, with the same validation and report as POST /products/import. It
+// runs against the database configured in .env, prints the report as JSON
+// and exits with status 1 when any row is invalid.
+func main() {
+	seller := flag.String("seller", "", "id or email of the seller the products are listed for")
+	file := flag.String("file", "", "path of the CSV or NDJSON file to import")
+	format := flag.String("format", "", "csv or ndjson, guessed from the file extension when empty")
+	publish := flag.Bool("publish", false, "publish the products as live auctions instead of drafts")
+	dryRun := flag.Bool("dry-run", false, "validate the whole import and roll it back")
+	chunkSize := flag.Int("chunk-size", 0, "rows per transaction; 0 creates nothing unless every row is valid")
+	flag.Parse()
+
+	if *seller == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".csv":
+			*format = product.ImportFormatCSV
+		case ".ndjson", ".jsonl":
+			*format = product.ImportFormatNDJSON
+		}
+	}
+
+	if err := godotenv.Load(); err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s",
+		os.Getenv("GOBID_DATABASE_USER"),
+		os.Getenv("GOBID_DATABASE_PASSWORD"),
+		os.Getenv("GOBID_DATABASE_HOST"),
+		os.Getenv("GOBID_DATABASE_PORT"),
+		os.Getenv("GOBID_DATABASE_NAME")))
+	if err != nil {
+		panic(err)
+	}
+
+	defer pool.Close()
+
+	sellerId, err := uuid.Parse(*seller)
+	if err != nil {
+		userService := services.NewUserService(pool)
+		if sellerId, err = userService.FindUserIdByEmail(ctx, *seller); err != nil {
+			fmt.Fprintf(os.Stderr, "seller %q: %v\n", *seller, err)
+			os.Exit(1)
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	defer f.Close()
+
+	rows, err := product.DecodeImport(f, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *file, err)
+		os.Exit(1)
+	}
+
+	importService := services.NewImportService(pool)
+	report, err := importService.ImportProducts(ctx, sellerId, rows, services.ImportOptions{
+		Publish:   *publish,
+		DryRun:    *dryRun,
+		ChunkSize: *chunkSize,
+	})
+	if errors.Is(err, services.ErrEmailNotVerified) {
+		fmt.Fprintf(os.Stderr, "seller %q: %v\n", *seller, err)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		panic(err)
+	}
+
+	if report.Invalid > 0 {
+		os.Exit(1)
+	}
+}
@@ -13,9 +13,9 @@ func main() {
 	}
 
 	cmd := exec.Command(
-		"tern", 
-		"migrate", 
-		"--migrations", "./internal/store/pgstore/migrations", 
+		"tern",
+		"migrate",
+		"--migrations", "./internal/store/pgstore/migrations",
 		"--config", "./internal/store/pgstore/migrations/tern.conf",
 	)
 
@@ -28,4 +28,4 @@ func main() {
 	}
 
 	fmt.Println("command execution successfully: ", string(output))
-}
\ No newline at end of file
+}
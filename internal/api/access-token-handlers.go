@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/user"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+func (api *Api) handleCreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.CreateAccessTokenReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, data.Lifetime())
+
+	created, token, err := api.AccessTokenService.CreateToken(r.Context(), userId, data.Name, data.Scopes, expiresAt)
+	if err != nil {
+		if errors.Is(err, services.ErrDuplicatedTokenName) {
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"name": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusCreated, map[string]any{
+		"message":      "copy the token now, it will not be shown again",
+		"token":        token,
+		"access_token": created,
+	})
+}
+
+func (api *Api) handleListAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	tokens, err := api.AccessTokenService.ListTokens(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, tokens)
+}
+
+func (api *Api) handleRevokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	tokenId, err := uuid.Parse(chi.URLParam(r, "token_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid token id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.AccessTokenService.RevokeToken(r.Context(), userId, tokenId); err != nil {
+		if errors.Is(err, services.ErrAccessTokenNotFound) {
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "access token revoked"})
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+func (api *Api) handleListShillFlags(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = services.ShillFlagPending
+	case services.ShillFlagPending, services.ShillFlagDismissed, services.ShillFlagConfirmed:
+	default:
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "status must be pending, dismissed or confirmed"})
+		return
+	}
+
+	flags, err := api.ShillService.ListFlags(r.Context(), status)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"flags": flags})
+}
+
+func (api *Api) handleConfirmShillFlag(w http.ResponseWriter, r *http.Request) {
+	api.reviewShillFlag(w, r, true)
+}
+
+func (api *Api) handleDismissShillFlag(w http.ResponseWriter, r *http.Request) {
+	api.reviewShillFlag(w, r, false)
+}
+
+func (api *Api) reviewShillFlag(w http.ResponseWriter, r *http.Request, confirm bool) {
+	flagId, err := uuid.Parse(chi.URLParam(r, "flag_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid flag id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	flag, err := api.ShillService.ReviewFlag(r.Context(), flagId, userId, confirm)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrShillFlagNotFound):
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrShillFlagAlreadyReviewed):
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"flag_id": flag.ID, "status": flag.Status})
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net/netip"
+
 	"github.com/alexedwards/scs/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/websocket"
@@ -8,11 +10,26 @@ import (
 )
 
 type Api struct {
-	Router         *chi.Mux
-	UserService    services.UserService
-	ProductService services.ProductService
-	BidsService    services.BidsService
-	Sessions       *scs.SessionManager
-	WsUpgrader     websocket.Upgrader
-	AuctionLobby   services.AuctionLobby
+	Router                   *chi.Mux
+	UserService              services.UserService
+	EmailVerificationService services.EmailVerificationService
+	PasswordResetService     services.PasswordResetService
+	TwoFactorService         services.TwoFactorService
+	AccessTokenService       services.AccessTokenService
+	ProductService           services.ProductService
+	CategoryService          services.CategoryService
+	ImageService             services.ImageService
+	ImportService            services.ImportService
+	BidsService              services.BidsService
+	ChatService              services.ChatService
+	RetractionService        services.RetractionService
+	IdempotencyService       services.IdempotencyService
+	ShillService             services.ShillService
+	WalletService            services.WalletService
+	WatchlistService         services.WatchlistService
+	NotificationService      services.NotificationService
+	Sessions                 *scs.SessionManager
+	WsUpgrader               websocket.Upgrader
+	AuctionLobby             *services.AuctionLobby
+	TrustedProxies           []netip.Prefix
 }
@@ -11,6 +11,11 @@ import (
 )
 
 func (api *Api) handleSubscribeUserToAuction(w http.ResponseWriter, r *http.Request) {
+	if !api.AuctionLobby.Accepting() {
+		utils.EncodeJson(w, r, http.StatusServiceUnavailable, map[string]any{"message": "server is restarting, try again shortly"})
+		return
+	}
+
 	rawProductID := chi.URLParam(r, "product_id")
 
 	productId, err := uuid.Parse(rawProductID)
@@ -30,21 +35,25 @@ func (api *Api) handleSubscribeUserToAuction(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	userId, ok := api.Sessions.Get(r.Context(), "AuthenticateUserId").(uuid.UUID)
+	userId, ok := authenticatedUserId(r)
 	if !ok {
 		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
 		return
 	}
 
-	api.AuctionLobby.Lock()
-	room, ok := api.AuctionLobby.Rooms[productId]
-	api.AuctionLobby.Unlock()
+	room, ok := api.AuctionLobby.Room(productId)
 
 	if !ok {
 		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"message": "the auction for this product has ended or does not exist"})
 		return
 	}
 
+	isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
 	conn, err := api.WsUpgrader.Upgrade(w, r, nil)
 	if err != nil {
 		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "could not upgrade connection to websocket protocol"})
@@ -52,8 +61,39 @@ func (api *Api) handleSubscribeUserToAuction(w http.ResponseWriter, r *http.Requ
 	}
 
 	client := services.NewClient(room, conn, userId)
+	client.IsAdmin = isAdmin
+	client.Origin = api.requestOrigin(r)
+	client.ReadOnly = !requestAllows(r, services.ScopeBid)
+	client.Start()
+}
+
+func (api *Api) handleSubscribeUserToLobby(w http.ResponseWriter, r *http.Request) {
+	if !api.AuctionLobby.Accepting() {
+		utils.EncodeJson(w, r, http.StatusServiceUnavailable, map[string]any{"message": "server is restarting, try again shortly"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
+	isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
+	conn, err := api.WsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "could not upgrade connection to websocket protocol"})
+		return
+	}
 
-	room.Register <- client
-	go client.ReadEventLoop()
-	go client.WriteEventLoop()
+	client := services.NewLobbyClient(api.AuctionLobby, conn, userId)
+	client.IsAdmin = isAdmin
+	client.Origin = api.requestOrigin(r)
+	client.ReadOnly = !requestAllows(r, services.ScopeBid)
+	client.Start()
 }
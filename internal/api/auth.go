@@ -1,20 +1,167 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/csrf"
+	"github.com/gregoryAlvim/gobid/internal/services"
 	"github.com/gregoryAlvim/gobid/internal/utils"
 )
 
+type contextKey string
+
+const identityKey contextKey = "identity"
+
+// identity is who made a request: a logged in session, which may do
+// anything its user may, or a personal access token, which is limited to
+// its scopes.
+type identity struct {
+	UserID uuid.UUID
+	Token  *services.TokenIdentity
+}
+
+// authenticatedUserId returns the user behind r, whether it came with a
+// session or with a bearer token.
+func authenticatedUserId(r *http.Request) (uuid.UUID, bool) {
+	id, ok := r.Context().Value(identityKey).(identity)
+	return id.UserID, ok
+}
+
+// IdentifyMiddleware puts the identity of the request in its context. An
+// Authorization header must hold a valid bearer token; without one the
+// session cookie is used, and requests with neither stay anonymous.
+func (api *Api) IdentifyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get("Authorization"); header != "" {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				utils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"message": "authorization must be a bearer token"})
+				return
+			}
+
+			tokenIdentity, err := api.AccessTokenService.Authenticate(r.Context(), strings.TrimSpace(token))
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidAccessToken) {
+					utils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"message": err.Error()})
+					return
+				}
+
+				utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), identityKey, identity{UserID: tokenIdentity.UserID, Token: &tokenIdentity})
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		if userId, ok := api.Sessions.Get(r.Context(), "AuthenticateUserId").(uuid.UUID); ok {
+			current, err := api.sessionIsCurrent(r.Context(), userId)
+			if err != nil {
+				utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+				return
+			}
+
+			if !current {
+				if err := api.Sessions.Destroy(r.Context()); err != nil {
+					utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+					return
+				}
+			} else {
+				r = r.WithContext(context.WithValue(r.Context(), identityKey, identity{UserID: userId}))
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sessionIsCurrent reports whether the session in ctx was logged in under
+// the current session generation of userId. Changing or resetting the
+// password moves the generation on, which ends every older session.
+func (api *Api) sessionIsCurrent(ctx context.Context, userId uuid.UUID) (bool, error) {
+	generation, err := api.UserService.SessionGeneration(ctx, userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return api.Sessions.GetInt32(ctx, "SessionGeneration") == generation, nil
+}
+
+// requestAllows reports whether the identity of r may act within scope.
+// Sessions may do anything.
+func requestAllows(r *http.Request, scope string) bool {
+	id, ok := r.Context().Value(identityKey).(identity)
+	return ok && (id.Token == nil || id.Token.HasScope(scope))
+}
+
+// AuthMiddleware lets through logged in sessions and, for GET and HEAD
+// requests, access tokens with the read scope. Any other request made with
+// a token needs a route under RequireScope.
 func (api *Api) AuthMiddleware(next http.Handler) http.Handler {
+	return api.authenticate(next, "")
+}
+
+// RequireScope works like AuthMiddleware and also accepts access tokens
+// granted scope for requests that change something.
+func (api *Api) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return api.authenticate(next, scope)
+	}
+}
+
+func (api *Api) authenticate(next http.Handler, writeScope string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := r.Context().Value(identityKey).(identity)
+		if !ok {
+			utils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"message": "must be logged in"})
+			return
+		}
+
+		if id.Token != nil {
+			scope := writeScope
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = services.ScopeRead
+			}
+
+			if scope == "" || !id.Token.HasScope(scope) {
+				utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"message": "this access token is not allowed to access this resource"})
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (api *Api) AdminMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !api.Sessions.Exists(r.Context(), "AuthenticateUserId") {
+		userId, ok := authenticatedUserId(r)
+		if !ok {
 			utils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"message": "must be logged in"})
 			return
-		} else {
-			next.ServeHTTP(w, r)
 		}
+
+		isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+		if err != nil {
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+			return
+		}
+
+		if !isAdmin {
+			utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"message": "only admins can access this resource"})
+			return
+		}
+
+		next.ServeHTTP(w, r)
 	})
 }
 
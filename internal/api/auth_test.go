@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/alexedwards/scs/v2/memstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func init() {
+	gob.Register(uuid.UUID{})
+}
+
+// newAuthTestApi returns an Api whose router answers GET and POST /whoami
+// behind AuthMiddleware with the authenticated user id, and POST /login
+// with a session for the user id in the path.
+func newAuthTestApi(pool *pgxpool.Pool) *Api {
+	sessions := scs.New()
+	sessions.Store = memstore.New()
+
+	api := &Api{
+		Router:             chi.NewMux(),
+		Sessions:           sessions,
+		UserService:        services.NewUserService(pool),
+		AccessTokenService: services.NewAccessTokenService(pool),
+	}
+
+	api.Router.Use(api.Sessions.LoadAndSave, api.IdentifyMiddleware)
+	api.Router.Post("/login/{user_id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := api.logIn(r, uuid.MustParse(chi.URLParam(r, "user_id"))); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	whoami := func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := authenticatedUserId(r)
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		fmt.Fprint(w, userId)
+	}
+
+	api.Router.Group(func(r chi.Router) {
+		r.Use(api.AuthMiddleware)
+		r.Get("/whoami", whoami)
+		r.Post("/whoami", whoami)
+	})
+
+	return api
+}
+
+func serve(api *Api, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	api.Router.ServeHTTP(w, r)
+	return w
+}
+
+// testUser creates a user in the migrated database given as a connection
+// string in GOBID_TEST_DATABASE_URL, and skips the test when there is none.
+func testUser(t *testing.T) (*pgxpool.Pool, uuid.UUID) {
+	t.Helper()
+
+	dsn := os.Getenv("GOBID_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("GOBID_TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	suffix := uuid.NewString()[:8]
+
+	users := services.NewUserService(pool)
+
+	userId, err := users.CreateUser(ctx, "auth-test-"+suffix, "auth-test-"+suffix+"@test.local", "auth-test-password", "created by the auth middleware test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", userId); err != nil {
+			t.Errorf("cleanup: %v", err)
+		}
+	})
+
+	return pool, userId
+}
+
+func TestAuthMiddlewareAcceptsSessions(t *testing.T) {
+	pool, userId := testUser(t)
+	api := newAuthTestApi(pool)
+
+	if w := serve(api, httptest.NewRequest(http.MethodGet, "/whoami", nil)); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous request: got status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	login := serve(api, httptest.NewRequest(http.MethodPost, "/login/"+userId.String(), nil))
+	cookies := login.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("login did not set a session cookie")
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		r := httptest.NewRequest(method, "/whoami", nil)
+		r.AddCookie(cookies[0])
+
+		w := serve(api, r)
+		if w.Code != http.StatusOK || w.Body.String() != userId.String() {
+			t.Errorf("%s with session: got %d %q, want 200 %q", method, w.Code, w.Body.String(), userId)
+		}
+	}
+}
+
+func TestPasswordChangeEndsOtherSessions(t *testing.T) {
+	pool, userId := testUser(t)
+	api := newAuthTestApi(pool)
+
+	login := serve(api, httptest.NewRequest(http.MethodPost, "/login/"+userId.String(), nil))
+	cookies := login.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("login did not set a session cookie")
+	}
+
+	if _, err := api.UserService.ChangePassword(context.Background(), userId, "auth-test-password", "auth-test-password-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	r.AddCookie(cookies[0])
+
+	if w := serve(api, r); w.Code != http.StatusUnauthorized {
+		t.Errorf("session from before the password change: got status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestIdentifyMiddlewareRejectsInvalidBearer(t *testing.T) {
+	api := newAuthTestApi(nil)
+
+	for _, header := range []string{"Basic dXNlcjpwYXNz", "Bearer not-a-gobid-token"} {
+		r := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		r.Header.Set("Authorization", header)
+
+		if w := serve(api, r); w.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: got status %d, want %d", header, w.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestAuthMiddlewareAcceptsBearerTokens(t *testing.T) {
+	pool, userId := testUser(t)
+	api := newAuthTestApi(pool)
+
+	_, readToken, err := api.AccessTokenService.CreateToken(context.Background(), userId, "read", []string{services.ScopeRead}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		want   int
+	}{
+		{http.MethodGet, http.StatusOK},
+		// Token writes need a route under RequireScope.
+		{http.MethodPost, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/whoami", nil)
+		r.Header.Set("Authorization", "Bearer "+readToken)
+
+		w := serve(api, r)
+		if w.Code != tt.want {
+			t.Errorf("%s with bearer token: got status %d, want %d", tt.method, w.Code, tt.want)
+		}
+
+		if tt.want == http.StatusOK && w.Body.String() != userId.String() {
+			t.Errorf("%s with bearer token: got user %q, want %q", tt.method, w.Body.String(), userId)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/bid"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+func (api *Api) handlePlaceBid(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[bid.PlaceBidReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	api.withIdempotency(w, r, userId, "rest_bid:"+productId.String(), func() (int, any) {
+		product, err := api.ProductService.GetProductById(r.Context(), productId)
+		if err != nil {
+			if errors.Is(err, services.ErrProductNotFound) {
+				return http.StatusNotFound, map[string]any{"error": err.Error()}
+			}
+
+			return http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"}
+		}
+
+		amount, err := money.Parse(data.Amount.String(), product.Currency)
+		if err != nil {
+			return http.StatusUnprocessableEntity, map[string]any{"amount": err.Error()}
+		}
+
+		placed, err := api.BidsService.PlaceBid(r.Context(), productId, userId, amount, data.Units(), api.requestOrigin(r))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrBidTooLow):
+				return http.StatusUnprocessableEntity, map[string]any{"error": err.Error()}
+			case errors.Is(err, services.ErrInvalidBidQuantity):
+				return http.StatusUnprocessableEntity, map[string]any{"quantity": err.Error()}
+			case errors.Is(err, money.ErrOverflow):
+				return http.StatusUnprocessableEntity, map[string]any{"amount": err.Error()}
+			case errors.Is(err, services.ErrSellerCannotBid):
+				return http.StatusForbidden, map[string]any{"error": err.Error()}
+			case errors.Is(err, services.ErrEmailNotVerified):
+				return http.StatusForbidden, map[string]any{"error": err.Error(), "code": services.ErrCodeEmailNotVerified}
+			case errors.Is(err, services.ErrInsufficientFunds):
+				return http.StatusPaymentRequired, map[string]any{"error": err.Error(), "code": services.ErrCodeInsufficientFunds}
+			case errors.Is(err, services.ErrAuctionClosed), errors.Is(err, services.ErrAuctionNotStarted):
+				return http.StatusConflict, map[string]any{"error": err.Error()}
+			default:
+				return http.StatusInternalServerError, map[string]any{"error": "could not place your bid, try again later"}
+			}
+		}
+
+		if room, ok := api.AuctionLobby.Room(productId); ok {
+			room.AnnounceBid(placed)
+		}
+
+		return http.StatusCreated, map[string]any{"bid_id": placed.ID, "amount": money.New(placed.BidAmount, product.Currency), "quantity": placed.Quantity}
+	})
+}
+
+const (
+	defaultBidPageSize = 20
+	maxBidPageSize     = 100
+)
+
+func (api *Api) handleListProductBids(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	filter, problems := parseBidHistoryFilter(r)
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	page, err := api.BidsService.ListProductBids(r.Context(), productId, userId, isAdmin, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidCursor):
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, page)
+}
+
+func (api *Api) handleListAuctionResults(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	results, err := api.BidsService.ListAuctionResults(r.Context(), productId, userId, isAdmin)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, results)
+}
+
+// parseBidHistoryFilter reads the limit, cursor, from and to query
+// parameters. Times must be RFC 3339.
+func parseBidHistoryFilter(r *http.Request) (services.BidHistoryFilter, map[string]string) {
+	query := r.URL.Query()
+	problems := make(map[string]string)
+
+	filter := services.BidHistoryFilter{
+		Cursor: query.Get("cursor"),
+		Limit:  defaultBidPageSize,
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxBidPageSize {
+			problems["limit"] = fmt.Sprintf("must be a number between 1 and %d", maxBidPageSize)
+		} else {
+			filter.Limit = int32(limit)
+		}
+	}
+
+	if raw := query.Get("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			problems["from"] = "must be a RFC 3339 timestamp"
+		}
+		filter.CreatedAfter = from
+	}
+
+	if raw := query.Get("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			problems["to"] = "must be a RFC 3339 timestamp"
+		}
+		filter.CreatedBefore = to
+	}
+
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		problems["to"] = "must be after from"
+	}
+
+	return filter, problems
+}
+
+func (api *Api) handleListMyBids(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	problems := make(map[string]string)
+	page, limit := parseProductPage(r.URL.Query(), problems)
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	bids, err := api.BidsService.ListUserBids(r.Context(), userId, page, limit)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, bids)
+}
+
+func (api *Api) handleRequestBidRetraction(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.Parse(chi.URLParam(r, "bid_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid bid id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[bid.RequestRetractionReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	retraction, err := api.RetractionService.RequestRetraction(r.Context(), bidId, userId, data.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBidNotFound):
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrNotBidOwner):
+			utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrBidAlreadyVoid), errors.Is(err, services.ErrRetractionAlreadyRequested):
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusCreated, map[string]any{"retraction_id": retraction.ID, "status": retraction.Status})
+}
+
+func (api *Api) handleListBidRetractions(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	retractions, err := api.RetractionService.ListRetractions(r.Context(), productId, userId, isAdmin)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrNotAllowedToReview):
+			utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"retractions": retractions})
+}
+
+func (api *Api) handleApproveBidRetraction(w http.ResponseWriter, r *http.Request) {
+	api.reviewBidRetraction(w, r, true)
+}
+
+func (api *Api) handleRejectBidRetraction(w http.ResponseWriter, r *http.Request) {
+	api.reviewBidRetraction(w, r, false)
+}
+
+func (api *Api) reviewBidRetraction(w http.ResponseWriter, r *http.Request, approve bool) {
+	retractionId, err := uuid.Parse(chi.URLParam(r, "retraction_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid retraction id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	isAdmin, err := api.UserService.IsAdmin(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	review, err := api.RetractionService.ReviewRetraction(r.Context(), retractionId, userId, isAdmin, approve)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRetractionNotFound):
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrNotAllowedToReview):
+			utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrRetractionAlreadyReviewed), errors.Is(err, services.ErrAuctionClosed):
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	if approve {
+		if room, ok := api.AuctionLobby.Room(review.ProductID); ok {
+			room.Notify(services.Message{
+				Kind:     services.PriceCorrected,
+				Message:  "A bid was retracted, the current price has been corrected",
+				Amount:   json.Number(review.CurrentPrice.String()),
+				Currency: review.CurrentPrice.Currency,
+			})
+		}
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"retraction_id": review.Retraction.ID,
+		"status":        review.Retraction.Status,
+		"current_price": review.CurrentPrice,
+	})
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/category"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+func (api *Api) handleListCategories(w http.ResponseWriter, r *http.Request) {
+	tree, err := api.CategoryService.CategoryTree(r.Context())
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"categories": tree})
+}
+
+func (api *Api) handleListCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := uuid.Parse(chi.URLParam(r, "category_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid category id, must be a valid uuid"})
+		return
+	}
+
+	problems := make(map[string]string)
+	filter, sort, page, limit := parseProductListQuery(r.URL.Query(), problems)
+	filter.CategoryID = categoryId
+
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if _, err := api.CategoryService.GetCategoryById(r.Context(), categoryId); err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+
+	api.encodeProductPage(w, r, filter, sort, page, limit)
+}
+
+func (api *Api) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[category.SaveCategoryReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	created, err := api.CategoryService.CreateCategory(r.Context(), data.ParentID, data.Name)
+	if err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusCreated, created)
+}
+
+func (api *Api) handleUpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := uuid.Parse(chi.URLParam(r, "category_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid category id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[category.SaveCategoryReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	updated, err := api.CategoryService.UpdateCategory(r.Context(), categoryId, data.ParentID, data.Name)
+	if err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, updated)
+}
+
+func (api *Api) handleDeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryId, err := uuid.Parse(chi.URLParam(r, "category_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid category id, must be a valid uuid"})
+		return
+	}
+
+	if err := api.CategoryService.DeleteCategory(r.Context(), categoryId); err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "category deleted"})
+}
+
+func (api *Api) encodeCategoryError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrCategoryNameTaken), errors.Is(err, services.ErrCategoryCycle):
+		utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	default:
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+	}
+}
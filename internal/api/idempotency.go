@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	idempotentBodyKey contextKey = "idempotent_body"
+)
+
+// hashedBody hashes a request body as the handler reads it, so a request
+// with an idempotency key can be told apart from another one using the same
+// key without buffering the body.
+type hashedBody struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (b *hashedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	return n, err
+}
+
+// IdempotencyMiddleware hashes the body of requests carrying an
+// Idempotency-Key header for withIdempotency.
+func (api *Api) IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(idempotencyKeyHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body := &hashedBody{ReadCloser: r.Body, hash: sha256.New()}
+		r.Body = body
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), idempotentBodyKey, body)))
+	})
+}
+
+// idempotentRequestHash identifies the request by its method, path and body.
+// Whatever the handler left unread of the body is read here.
+func idempotentRequestHash(r *http.Request) []byte {
+	var bodyHash []byte
+	if body, ok := r.Context().Value(idempotentBodyKey).(*hashedBody); ok {
+		io.Copy(io.Discard, r.Body)
+		bodyHash = body.hash.Sum(nil)
+	}
+
+	return services.HashIdempotentRequest(r.Method, r.URL.Path, string(bodyHash))
+}
+
+// withIdempotency runs handle at most once per Idempotency-Key header. A
+// repeated key gets the status and body of the first request back, unless
+// it comes with a different request, which is refused; requests without the
+// header are handled normally. Server errors are not stored, so the client
+// may retry them with the same key.
+func (api *Api) withIdempotency(w http.ResponseWriter, r *http.Request, userId uuid.UUID, scope string, handle func() (int, any)) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" {
+		status, body := handle()
+		utils.EncodeJson(w, r, status, body)
+		return
+	}
+
+	if !validator.MaxChars(key, 255) {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "idempotency key must have at most 255 characters"})
+		return
+	}
+
+	result, replay, err := api.IdempotencyService.Begin(r.Context(), userId, scope, key, idempotentRequestHash(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyMismatch):
+			utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if replay {
+		w.Header().Set("Idempotent-Replayed", "true")
+		utils.EncodeJson(w, r, result.StatusCode, result.Body)
+		return
+	}
+
+	status, body := handle()
+
+	if status >= http.StatusInternalServerError {
+		err = api.IdempotencyService.Release(r.Context(), userId, scope, key)
+	} else {
+		err = api.IdempotencyService.Complete(r.Context(), userId, scope, key, status, body)
+	}
+
+	if err != nil {
+		slog.Error("failed to store idempotency key", "scope", scope, "error", err)
+	}
+
+	utils.EncodeJson(w, r, status, body)
+}
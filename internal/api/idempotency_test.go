@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// requestHash sends a request through IdempotencyMiddleware to a handler
+// that reads part of the body, like a JSON decoder may, and returns the
+// hash withIdempotency would see.
+func requestHash(t *testing.T, method, path, body string) []byte {
+	t.Helper()
+
+	var hash []byte
+	api := &Api{}
+	handler := api.IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadFull(r.Body, make([]byte, min(len(body), 4)))
+		hash = idempotentRequestHash(r)
+	}))
+
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set(idempotencyKeyHeader, "key")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	return hash
+}
+
+func TestIdempotentRequestHash(t *testing.T) {
+	original := requestHash(t, http.MethodPost, "/api/v1/products", `{"product_name":"lamp"}`)
+
+	if again := requestHash(t, http.MethodPost, "/api/v1/products", `{"product_name":"lamp"}`); !bytes.Equal(original, again) {
+		t.Error("the same request hashed differently")
+	}
+
+	others := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/api/v1/products", `{"product_name":"desk"}`},
+		{http.MethodPost, "/api/v1/products/import", `{"product_name":"lamp"}`},
+		{http.MethodPut, "/api/v1/products", `{"product_name":"lamp"}`},
+	}
+
+	for _, other := range others {
+		if bytes.Equal(original, requestHash(t, other.method, other.path, other.body)) {
+			t.Errorf("%s %s %s hashed like the original request", other.method, other.path, other.body)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/product"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+// multipartOverhead leaves room for the multipart boundaries and headers on
+// top of the image itself.
+const multipartOverhead = 1 << 20
+
+func (api *Api) handleUploadProductImage(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxImageBytes+multipartOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "the request must be a multipart/form-data upload"})
+		return
+	}
+
+	var data []byte
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			api.encodeUploadReadError(w, r, err)
+			return
+		}
+
+		if part.FormName() != "image" {
+			continue
+		}
+
+		data, err = io.ReadAll(io.LimitReader(part, services.MaxImageBytes+1))
+		if err != nil {
+			api.encodeUploadReadError(w, r, err)
+			return
+		}
+
+		break
+	}
+
+	if data == nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"image": "this field is required"})
+		return
+	}
+
+	image, err := api.ImageService.AddProductImage(r.Context(), productId, userId, data)
+	if err != nil {
+		api.encodeImageError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusCreated, image)
+}
+
+func (api *Api) encodeUploadReadError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		utils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": services.ErrImageTooLarge.Error()})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "could not read the uploaded file"})
+}
+
+func (api *Api) handleGetProductImage(w http.ResponseWriter, r *http.Request) {
+	api.serveProductImage(w, r, false)
+}
+
+func (api *Api) handleGetProductImageThumbnail(w http.ResponseWriter, r *http.Request) {
+	api.serveProductImage(w, r, true)
+}
+
+func (api *Api) serveProductImage(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	imageId, err := uuid.Parse(chi.URLParam(r, "image_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid image id, must be a valid uuid"})
+		return
+	}
+
+	viewerId, _ := authenticatedUserId(r)
+
+	content, contentType, err := api.ImageService.OpenProductImage(r.Context(), productId, imageId, viewerId, thumbnail)
+	if err != nil {
+		api.encodeImageError(w, r, err)
+		return
+	}
+
+	defer content.Close()
+
+	// Image ids are never reused, so the content behind a URL never changes.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+func (api *Api) handleDeleteProductImage(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	imageId, err := uuid.Parse(chi.URLParam(r, "image_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid image id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.ImageService.DeleteProductImage(r.Context(), productId, imageId, userId); err != nil {
+		api.encodeImageError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "image deleted"})
+}
+
+func (api *Api) handleReorderProductImages(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[product.ReorderImagesReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	images, err := api.ImageService.ReorderProductImages(r.Context(), productId, userId, data.ImageIDs)
+	if err != nil {
+		api.encodeImageError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"images": images})
+}
+
+func (api *Api) encodeImageError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound), errors.Is(err, services.ErrImageNotFound):
+		utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrNotProductSeller):
+		utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrAuctionClosed), errors.Is(err, services.ErrTooManyImages):
+		utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrImageTooLarge):
+		utils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrUnsupportedImage), errors.Is(err, services.ErrImageDimensions):
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"image": err.Error()})
+	case errors.Is(err, services.ErrInvalidImageOrder):
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"image_ids": err.Error()})
+	default:
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+	}
+}
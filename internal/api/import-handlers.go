@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/product"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+const maxImportBytes = 5 << 20
+
+// importFormats maps the content types accepted by the import endpoint to
+// the file format they carry.
+var importFormats = map[string]string{
+	"text/csv":             product.ImportFormatCSV,
+	"application/x-ndjson": product.ImportFormatNDJSON,
+	"application/jsonl":    product.ImportFormatNDJSON,
+}
+
+func (api *Api) handleImportProducts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	problems := make(map[string]string)
+
+	format := query.Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importFormats[mediaType]
+	}
+
+	if format != product.ImportFormatCSV && format != product.ImportFormatNDJSON {
+		problems["format"] = "must be csv or ndjson, either in the query or as a text/csv or application/x-ndjson body"
+	}
+
+	opts := services.ImportOptions{}
+	for key, target := range map[string]*bool{"publish": &opts.Publish, "dry_run": &opts.DryRun} {
+		if raw := query.Get(key); raw != "" {
+			value, err := strconv.ParseBool(raw)
+			if err != nil {
+				problems[key] = "must be true or false"
+			}
+			*target = value
+		}
+	}
+
+	if raw := query.Get("chunk_size"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > product.MaxImportRows {
+			problems["chunk_size"] = fmt.Sprintf("must be a number between 1 and %d", product.MaxImportRows)
+		} else {
+			opts.ChunkSize = value
+		}
+	}
+
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	rows, err := product.DecodeImport(r.Body, format)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": fmt.Sprintf("the import file must have at most %d MiB", maxImportBytes>>20)})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+
+	api.withIdempotency(w, r, userId, "import_products", func() (int, any) {
+		report, err := api.ImportService.ImportProducts(r.Context(), userId, rows, opts)
+		if err != nil {
+			if errors.Is(err, services.ErrEmailNotVerified) {
+				return http.StatusForbidden, map[string]any{"error": err.Error(), "code": services.ErrCodeEmailNotVerified}
+			}
+
+			return http.StatusInternalServerError, map[string]any{"error": "failed to import products, try again later"}
+		}
+
+		if report.Created > 0 {
+			if err := api.UserService.RecordOrigin(r.Context(), userId, api.requestOrigin(r)); err != nil {
+				slog.Error("failed to record seller origin", "user_id", userId, "error", err)
+			}
+		}
+
+		switch {
+		case report.DryRun:
+			return http.StatusOK, report
+		case report.Created > 0:
+			return http.StatusCreated, report
+		case report.Invalid > 0:
+			return http.StatusUnprocessableEntity, report
+		default:
+			return http.StatusOK, report
+		}
+	})
+}
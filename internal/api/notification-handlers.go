@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+const (
+	defaultNotificationPageSize = 50
+	maxNotificationPageSize     = 200
+)
+
+func (api *Api) handleListNotifications(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	problems := make(map[string]string)
+
+	limit := int32(defaultNotificationPageSize)
+	if raw := query.Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxNotificationPageSize {
+			problems["limit"] = fmt.Sprintf("must be a number between 1 and %d", maxNotificationPageSize)
+		} else {
+			limit = int32(value)
+		}
+	}
+
+	unreadOnly := false
+	if raw := query.Get("unread"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			problems["unread"] = "must be true or false"
+		}
+		unreadOnly = value
+	}
+
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	inbox, err := api.NotificationService.ListNotifications(r.Context(), userId, unreadOnly, limit)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, inbox)
+}
+
+func (api *Api) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	notificationId, err := uuid.Parse(chi.URLParam(r, "notification_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid notification id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	notification, err := api.NotificationService.MarkNotificationRead(r.Context(), userId, notificationId)
+	if err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, notification)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gregoryAlvim/gobid/internal/services"
+)
+
+const (
+	deviceIdHeader     = "X-Device-Id"
+	maxDeviceIdLength  = 128
+	forwardedForHeader = "X-Forwarded-For"
+)
+
+// ParseTrustedProxies reads a comma separated list of addresses or CIDR
+// prefixes, such as "10.0.0.0/8,127.0.0.1", of the proxies allowed to tell
+// the client address in X-Forwarded-For.
+func ParseTrustedProxies(list string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+
+		addr = addr.Unmap()
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return proxies, nil
+}
+
+// requestOrigin reads the client address and the optional device id sent by
+// the client apps in the X-Device-Id header.
+func (api *Api) requestOrigin(r *http.Request) services.RequestOrigin {
+	var origin services.RequestOrigin
+
+	origin.IP = api.clientIP(r)
+
+	if deviceId := r.Header.Get(deviceIdHeader); len(deviceId) <= maxDeviceIdLength {
+		origin.DeviceID = deviceId
+	}
+
+	return origin
+}
+
+// clientIP is the address of the peer, unless the peer is one of the
+// trusted proxies. Then X-Forwarded-For is read from the right, skipping the
+// trusted proxies, and the first address that is not one of them is the
+// client: entries further left were written by the client itself and can be
+// forged.
+func (api *Api) clientIP(r *http.Request) netip.Addr {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	ip := addrPort.Addr().Unmap()
+	if !api.isTrustedProxy(ip) {
+		return ip
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values(forwardedForHeader) {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+
+		ip = hop.Unmap()
+		if !api.isTrustedProxy(ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func (api *Api) isTrustedProxy(ip netip.Addr) bool {
+	for _, proxy := range api.TrustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,14 +2,28 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
 	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
 	"github.com/gregoryAlvim/gobid/internal/usecase/product"
 	"github.com/gregoryAlvim/gobid/internal/utils"
 )
 
+func (api *Api) openAuctionRoom(product pgstore.Product) error {
+	auctionRoom := services.NewAuctionRoom(context.Background(), product, api.BidsService, api.ChatService, api.IdempotencyService)
+	return api.AuctionLobby.Open(auctionRoom)
+}
+
 func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
 	data, problems, err := utils.DecodeValidJson[product.CreateProductReq](r)
 	if err != nil {
@@ -17,25 +31,485 @@ func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, ok := api.Sessions.Get(r.Context(), "AuthenticateUserId").(uuid.UUID)
+	userID, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	api.withIdempotency(w, r, userID, "create_product", func() (int, any) {
+		basePrice, err := money.Parse(data.BasePrice.String(), data.Currency)
+		if err != nil {
+			return http.StatusUnprocessableEntity, map[string]any{"base_price": err.Error()}
+		}
+
+		var reservePrice *money.Money
+		if data.ReservePrice != "" {
+			reserve, err := money.Parse(data.ReservePrice.String(), data.Currency)
+			if err != nil {
+				return http.StatusUnprocessableEntity, map[string]any{"reserve_price": err.Error()}
+			}
+
+			reservePrice = &reserve
+		}
+
+		product, err := api.ProductService.CreateProduct(r.Context(), services.ProductListing{
+			SellerID:     userID,
+			Name:         data.ProductName,
+			Description:  data.Description,
+			BasePrice:    basePrice,
+			ReservePrice: reservePrice,
+			StartsAt:     data.StartsAt,
+			AuctionEnd:   data.AuctionEnd,
+			CategoryID:   data.CategoryID,
+			Tags:         data.Tags,
+			Quantity:     data.Quantity,
+			Pricing:      data.Pricing,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrEmailNotVerified) {
+				return http.StatusForbidden, map[string]any{"error": err.Error(), "code": services.ErrCodeEmailNotVerified}
+			}
+
+			if errors.Is(err, services.ErrCategoryNotFound) {
+				return http.StatusUnprocessableEntity, map[string]any{"category_id": "must be an existing category"}
+			}
+
+			return http.StatusInternalServerError, map[string]any{"error": "failed to create product draft, try again later"}
+		}
+
+		if err := api.UserService.RecordOrigin(r.Context(), userID, api.requestOrigin(r)); err != nil {
+			slog.Error("failed to record seller origin", "user_id", userID, "error", err)
+		}
+
+		return http.StatusCreated, map[string]any{"message": "Draft created with success", "product_id": product.ID.String()}
+	})
+}
+
+func (api *Api) handlePreviewProduct(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	preview, err := api.ProductService.PreviewDraft(r.Context(), productId, userId)
+	if err != nil {
+		api.encodeProductChangeError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, preview)
+}
+
+func (api *Api) handlePublishProduct(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	api.withIdempotency(w, r, userId, "publish_product", func() (int, any) {
+		if !api.AuctionLobby.Accepting() {
+			return http.StatusServiceUnavailable, map[string]any{"error": "server is restarting, try again shortly"}
+		}
+
+		published, err := api.ProductService.PublishProduct(r.Context(), productId, userId)
+		if err != nil {
+			var listingErr *services.ListingError
+			switch {
+			case errors.As(err, &listingErr):
+				return http.StatusUnprocessableEntity, listingErr.Problems
+			case errors.Is(err, services.ErrProductNotFound):
+				return http.StatusNotFound, map[string]any{"error": err.Error()}
+			case errors.Is(err, services.ErrNotProductSeller):
+				return http.StatusForbidden, map[string]any{"error": err.Error()}
+			case errors.Is(err, services.ErrProductPublished):
+				return http.StatusConflict, map[string]any{"error": err.Error()}
+			default:
+				return http.StatusInternalServerError, map[string]any{"error": "failed to publish product, try again later"}
+			}
+		}
+
+		if published.StartsAt.After(time.Now()) {
+			return http.StatusOK, map[string]any{"message": "Auction scheduled with success", "product_id": published.ID.String(), "starts_at": published.StartsAt}
+		}
+
+		// The scheduler would open the room on its next tick anyway, this
+		// only spares the bidders the wait.
+		if err := api.openAuctionRoom(published); err != nil {
+			slog.Error("failed to open auction room", "product_id", published.ID, "error", err)
+		}
+
+		return http.StatusOK, map[string]any{"message": "Auction has started with success", "product_id": published.ID.String()}
+	})
+}
+
+func (api *Api) handleListMyDrafts(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	drafts, err := api.ProductService.ListDrafts(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, drafts)
+}
+
+const (
+	defaultProductPageSize = 20
+	maxProductPageSize     = 100
+)
+
+func (api *Api) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	problems := make(map[string]string)
+	filter, sort, page, limit := parseProductListQuery(r.URL.Query(), problems)
+
+	if raw := r.URL.Query().Get("category_id"); raw != "" {
+		categoryId, err := uuid.Parse(raw)
+		if err != nil {
+			problems["category_id"] = "must be a valid uuid"
+		} else {
+			filter.CategoryID = categoryId
+		}
+	}
+
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	api.encodeProductPage(w, r, filter, sort, page, limit)
+}
+
+func (api *Api) encodeProductPage(w http.ResponseWriter, r *http.Request, filter services.ProductFilter, sort string, page, limit int32) {
+	products, err := api.ProductService.ListProducts(r.Context(), filter, sort, page, limit)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, products)
+}
+
+// parseProductListQuery reads the catalog query string shared by the product
+// listing and the category browse endpoints.
+func parseProductListQuery(query url.Values, problems map[string]string) (services.ProductFilter, string, int32, int32) {
+	filter := services.ProductFilter{
+		Status: query.Get("status"),
+		Tag:    query.Get("tag"),
+	}
+
+	switch filter.Status {
+	case "":
+		filter.Status = services.AuctionStatusLive
+	case services.AuctionStatusLive, services.AuctionStatusEnded, services.AuctionStatusUpcoming, services.AuctionStatusAll:
+	default:
+		problems["status"] = "must be live, ended, upcoming or all"
+	}
+
+	sort := query.Get("sort")
+	switch sort {
+	case "":
+		sort = services.ProductSortEndingSoon
+	case services.ProductSortEndingSoon, services.ProductSortNewest, services.ProductSortPriceAsc, services.ProductSortPriceDesc:
+	default:
+		problems["sort"] = "must be ending_soon, newest, price_asc or price_desc"
+	}
+
+	page, limit := parseProductPage(query, problems)
+
+	return filter, sort, page, limit
+}
+
+func parseProductPage(query url.Values, problems map[string]string) (int32, int32) {
+	page := int32(1)
+	if raw := query.Get("page"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			problems["page"] = "must be a positive number"
+		} else {
+			page = int32(value)
+		}
+	}
+
+	limit := int32(defaultProductPageSize)
+	if raw := query.Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxProductPageSize {
+			problems["limit"] = fmt.Sprintf("must be a number between 1 and %d", maxProductPageSize)
+		} else {
+			limit = int32(value)
+		}
+	}
+
+	return page, limit
+}
+
+func (api *Api) handleSearchProducts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	problems := make(map[string]string)
+
+	search := services.ProductSearch{
+		Query:    query.Get("q"),
+		Language: query.Get("lang"),
+		Currency: query.Get("currency"),
+		Status:   query.Get("status"),
+	}
+
+	if search.Query == "" {
+		problems["q"] = "must not be empty"
+	}
+
+	if search.Language == "" {
+		search.Language = "pt"
+	} else if _, ok := services.SearchLanguages[search.Language]; !ok {
+		problems["lang"] = "must be pt or en"
+	}
+
+	switch search.Status {
+	case "":
+		search.Status = services.AuctionStatusAll
+	case services.AuctionStatusLive, services.AuctionStatusEnded, services.AuctionStatusUpcoming, services.AuctionStatusAll:
+	default:
+		problems["status"] = "must be live, ended, upcoming or all"
+	}
+
+	// Prices are only comparable within one currency, so a price filter
+	// needs to say which one it is in.
+	for key, target := range map[string]**money.Money{"min_price": &search.MinPrice, "max_price": &search.MaxPrice} {
+		raw := query.Get(key)
+		if raw == "" {
+			continue
+		}
+
+		if search.Currency == "" {
+			problems["currency"] = "is required when filtering by price"
+			continue
+		}
+
+		price, err := money.Parse(raw, search.Currency)
+		if err != nil {
+			problems[key] = err.Error()
+			continue
+		}
+
+		*target = &price
+	}
+
+	if raw := query.Get("ending_before"); raw != "" {
+		endingBefore, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			problems["ending_before"] = "must be a RFC 3339 timestamp"
+		} else {
+			search.EndingBefore = endingBefore
+		}
+	}
+
+	search.Page, search.Limit = parseProductPage(query, problems)
+
+	if len(problems) > 0 {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	results, err := api.ProductService.SearchProducts(r.Context(), search)
+	if err != nil {
+		if errors.Is(err, services.ErrEmptySearchQuery) {
+			utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"q": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, results)
+}
+
+func (api *Api) handleGetProduct(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	viewerId, _ := authenticatedUserId(r)
+
+	product, err := api.ProductService.GetProductDetails(r.Context(), productId, viewerId)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, product)
+}
+
+func (api *Api) handleUpdateProduct(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[product.UpdateProductReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	changes := services.ProductChanges{
+		ProductName: data.ProductName,
+		Description: data.Description,
+		StartsAt:    data.StartsAt,
+		AuctionEnd:  data.AuctionEnd,
+		CategoryID:  data.CategoryID,
+		Tags:        data.Tags,
+		Quantity:    data.Quantity,
+		Pricing:     data.Pricing,
+	}
+
+	if data.BasePrice != nil {
+		basePrice, err := money.Parse(data.BasePrice.String(), *data.Currency)
+		if err != nil {
+			utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"base_price": err.Error()})
+			return
+		}
+
+		changes.BasePrice = &basePrice
+	}
+
+	if data.ReservePrice != nil {
+		reservePrice := money.New(0, *data.Currency)
+		if !data.RemovesReserve() {
+			reservePrice, err = money.Parse(data.ReservePrice.String(), *data.Currency)
+			if err != nil {
+				utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"reserve_price": err.Error()})
+				return
+			}
+		}
+
+		changes.ReservePrice = &reservePrice
+	}
+
+	updated, err := api.ProductService.UpdateProduct(r.Context(), productId, userId, changes)
+	if err != nil {
+		api.encodeProductChangeError(w, r, err)
+		return
+	}
+
+	if room, ok := api.AuctionLobby.Room(productId); ok {
+		room.UpdateProduct(updated)
+	}
+
+	details, err := api.ProductService.GetProductDetails(r.Context(), productId, userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, details)
+}
+
+func (api *Api) handleDeleteProduct(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.ProductService.DeleteProduct(r.Context(), productId, userId); err != nil {
+		api.encodeProductChangeError(w, r, err)
+		return
+	}
+
+	if room, ok := api.AuctionLobby.Room(productId); ok {
+		room.Cancel()
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "product deleted"})
+}
+
+func (api *Api) handleSetBidHold(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[product.BidHoldReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
 	if !ok {
 		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
 		return
 	}
 
-	productId, err := api.ProductService.CreateProduct(r.Context(), userID, data.ProductName, data.Description, data.BasePrice, data.AuctionEnd)
+	updated, err := api.ProductService.SetBidHold(r.Context(), productId, userId, data.Percent)
 	if err != nil {
-		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "failed to create product auction, try again later"})
+		api.encodeProductChangeError(w, r, err)
 		return
 	}
 
-	ctx, _ := context.WithDeadline(context.Background(), data.AuctionEnd)
-	auctionRoom := services.NewAuctionRoom(ctx, productId, api.BidsService)
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"bid_hold_percent": updated.BidHoldPercent})
+}
 
-	go auctionRoom.Run()
-	api.AuctionLobby.Lock()
-	api.AuctionLobby.Rooms[productId] = auctionRoom
-	api.AuctionLobby.Unlock()
+func (api *Api) encodeProductChangeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrNotProductSeller):
+		utils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrProductHasBids), errors.Is(err, services.ErrAuctionClosed), errors.Is(err, services.ErrProductPublished),
+		errors.Is(err, services.ErrAuctionNotEnded), errors.Is(err, services.ErrProductSold), errors.Is(err, services.ErrProductRelisted):
+		utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrCategoryNotFound):
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"category_id": "must be an existing category"})
+	default:
+		var listingErr *services.ListingError
+		if errors.As(err, &listingErr) {
+			utils.EncodeJson(w, r, http.StatusUnprocessableEntity, listingErr.Problems)
+			return
+		}
 
-	utils.EncodeJson(w, r, http.StatusCreated, map[string]any{"message": "Auction has started with success", "product_id": productId.String()})
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+	}
 }
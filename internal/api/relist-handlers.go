@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/product"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+func (api *Api) handleRelistProduct(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[product.RelistProductReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	api.withIdempotency(w, r, userId, "relist_product", func() (int, any) {
+		if !api.AuctionLobby.Accepting() {
+			return http.StatusServiceUnavailable, map[string]any{"error": "server is restarting, try again shortly"}
+		}
+
+		original, err := api.ProductService.GetProductById(r.Context(), productId)
+		if err != nil {
+			if errors.Is(err, services.ErrProductNotFound) {
+				return http.StatusNotFound, map[string]any{"error": err.Error()}
+			}
+
+			return http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"}
+		}
+
+		opts := services.RelistOptions{}
+		if data.AuctionEnd != nil {
+			opts.AuctionEnd = *data.AuctionEnd
+		}
+
+		if data.BasePrice != nil {
+			basePrice, err := money.Parse(data.BasePrice.String(), original.Currency)
+			if err != nil {
+				return http.StatusUnprocessableEntity, map[string]any{"base_price": err.Error()}
+			}
+
+			opts.BasePrice = &basePrice
+		}
+
+		relisted, err := api.ProductService.RelistProduct(r.Context(), productId, userId, opts)
+		if err != nil {
+			var listingErr *services.ListingError
+			switch {
+			case errors.As(err, &listingErr):
+				return http.StatusUnprocessableEntity, listingErr.Problems
+			case errors.Is(err, services.ErrProductNotFound):
+				return http.StatusNotFound, map[string]any{"error": err.Error()}
+			case errors.Is(err, services.ErrNotProductSeller):
+				return http.StatusForbidden, map[string]any{"error": err.Error()}
+			case errors.Is(err, services.ErrAuctionNotEnded), errors.Is(err, services.ErrProductSold), errors.Is(err, services.ErrProductRelisted):
+				return http.StatusConflict, map[string]any{"error": err.Error()}
+			default:
+				return http.StatusInternalServerError, map[string]any{"error": "failed to relist product, try again later"}
+			}
+		}
+
+		// Like on publish, the scheduler would open the room anyway.
+		if err := api.openAuctionRoom(relisted); err != nil {
+			slog.Error("failed to open auction room", "product_id", relisted.ID, "error", err)
+		}
+
+		return http.StatusCreated, map[string]any{"message": "Product relisted with success", "product_id": relisted.ID.String()}
+	})
+}
+
+func (api *Api) handleSetRelistPolicy(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	data, problems, err := utils.DecodeValidJson[product.RelistPolicyReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	policy, err := api.ProductService.SetRelistPolicy(r.Context(), productId, userId, services.RelistPolicy{
+		MaxRelists:       data.MaxRelists,
+		PriceDropPercent: data.PriceDropPercent,
+	})
+	if err != nil {
+		api.encodeProductChangeError(w, r, err)
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, policy)
+}
+
+func (api *Api) handleListRelists(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id, must be a valid uuid"})
+		return
+	}
+
+	viewerId, _ := authenticatedUserId(r)
+
+	history, err := api.ProductService.RelistHistory(r.Context(), productId, viewerId)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, history)
+}
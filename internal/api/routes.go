@@ -3,10 +3,11 @@ package api
 import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gregoryAlvim/gobid/internal/services"
 )
 
 func (api *Api) BindRoutes() {
-	api.Router.Use(middleware.RequestID, middleware.Recoverer, middleware.Logger, api.Sessions.LoadAndSave)
+	api.Router.Use(middleware.RequestID, middleware.Recoverer, middleware.Logger, api.Sessions.LoadAndSave, api.IdentifyMiddleware, api.IdempotencyMiddleware)
 
 	// csrfMiddleware := csrf.Protect(
 	// 	[]byte(os.Getenv("GOBID_CSRF_SECRET")),
@@ -22,20 +23,114 @@ func (api *Api) BindRoutes() {
 			r.Route("/users", func(r chi.Router) {
 				r.Post("/signup", api.handleSignUpUser)
 				r.Post("/login", api.handleLoginUser)
+				r.Post("/login/2fa", api.handleLoginTwoFactor)
+				r.Post("/email/verify", api.handleVerifyEmail)
+				r.Post("/password/forgot", api.handleForgotPassword)
+				r.Post("/password/reset", api.handleResetPassword)
+				r.Get("/{user_id}", api.handleGetUserProfile)
 
 				r.Group(func(r chi.Router) {
 					r.Use(api.AuthMiddleware)
 
 					r.Post("/logout", api.handleLogoutUser)
+					r.Get("/me", api.handleGetMyProfile)
+					r.Patch("/me", api.handleUpdateMyProfile)
+					r.Post("/me/password", api.handleChangePassword)
+					r.Post("/me/email/verification", api.handleResendVerification)
+					r.Get("/me/2fa", api.handleGetTwoFactorStatus)
+					r.Post("/me/2fa/enroll", api.handleEnrollTwoFactor)
+					r.Post("/me/2fa/confirm", api.handleConfirmTwoFactor)
+					r.Post("/me/2fa/disable", api.handleDisableTwoFactor)
+					r.Get("/me/tokens", api.handleListAccessTokens)
+					r.Post("/me/tokens", api.handleCreateAccessToken)
+					r.Delete("/me/tokens/{token_id}", api.handleRevokeAccessToken)
+					r.Get("/me/bids", api.handleListMyBids)
+					r.Get("/me/drafts", api.handleListMyDrafts)
+					r.Get("/me/wallet", api.handleGetWallet)
+					r.Post("/me/wallet/deposits", api.handleDeposit)
+					r.Post("/me/wallet/withdrawals", api.handleWithdraw)
+					r.Get("/me/watchlist", api.handleListWatchlist)
+					r.Put("/me/watchlist/{product_id}", api.handleWatchProduct)
+					r.Delete("/me/watchlist/{product_id}", api.handleUnwatchProduct)
+					r.Get("/me/notifications", api.handleListNotifications)
+					r.Post("/me/notifications/{notification_id}/read", api.handleMarkNotificationRead)
 				})
 			})
 
 			r.Route("/products", func(r chi.Router) {
+				r.Get("/", api.handleListProducts)
+				r.Get("/search", api.handleSearchProducts)
+				r.Get("/{product_id}", api.handleGetProduct)
+				r.Get("/{product_id}/images/{image_id}", api.handleGetProductImage)
+				r.Get("/{product_id}/images/{image_id}/thumbnail", api.handleGetProductImageThumbnail)
+				r.Get("/{product_id}/relists", api.handleListRelists)
+
 				r.Group(func(r chi.Router) {
 					r.Use(api.AuthMiddleware)
 
-					r.Post("/", api.handleCreateProduct)
+					r.Get("/{product_id}/preview", api.handlePreviewProduct)
 					r.Get("/ws/subscribe/{product_id}", api.handleSubscribeUserToAuction)
+					r.Get("/ws/lobby", api.handleSubscribeUserToLobby)
+					r.Get("/{product_id}/retractions", api.handleListBidRetractions)
+					r.Get("/{product_id}/bids", api.handleListProductBids)
+					r.Get("/{product_id}/results", api.handleListAuctionResults)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireScope(services.ScopeSell))
+
+					r.Post("/", api.handleCreateProduct)
+					r.Post("/import", api.handleImportProducts)
+					r.Patch("/{product_id}", api.handleUpdateProduct)
+					r.Delete("/{product_id}", api.handleDeleteProduct)
+					r.Post("/{product_id}/publish", api.handlePublishProduct)
+					r.Post("/{product_id}/relist", api.handleRelistProduct)
+					r.Put("/{product_id}/relist-policy", api.handleSetRelistPolicy)
+					r.Put("/{product_id}/bid-hold", api.handleSetBidHold)
+					r.Post("/{product_id}/images", api.handleUploadProductImage)
+					r.Put("/{product_id}/images/order", api.handleReorderProductImages)
+					r.Delete("/{product_id}/images/{image_id}", api.handleDeleteProductImage)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireScope(services.ScopeBid))
+
+					r.Post("/{product_id}/bids", api.handlePlaceBid)
+				})
+			})
+
+			r.Route("/categories", func(r chi.Router) {
+				r.Get("/", api.handleListCategories)
+				r.Get("/{category_id}/products", api.handleListCategoryProducts)
+			})
+
+			r.Route("/bids", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireScope(services.ScopeBid))
+
+					r.Post("/{bid_id}/retractions", api.handleRequestBidRetraction)
+				})
+			})
+
+			r.Route("/retractions", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.RequireScope(services.ScopeSell))
+
+					r.Post("/{retraction_id}/approve", api.handleApproveBidRetraction)
+					r.Post("/{retraction_id}/reject", api.handleRejectBidRetraction)
+				})
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.AuthMiddleware, api.AdminMiddleware)
+
+					r.Get("/shill-flags", api.handleListShillFlags)
+					r.Post("/shill-flags/{flag_id}/confirm", api.handleConfirmShillFlag)
+					r.Post("/shill-flags/{flag_id}/dismiss", api.handleDismissShillFlag)
+					r.Post("/categories", api.handleCreateCategory)
+					r.Put("/categories/{category_id}", api.handleUpdateCategory)
+					r.Delete("/categories/{category_id}", api.handleDeleteCategory)
 				})
 			})
 		})
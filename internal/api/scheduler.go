@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gregoryAlvim/gobid/internal/services"
+)
+
+// RunAuctionScheduler opens a room for every published auction that has
+// started and has none, every interval until ctx is cancelled. It starts the
+// scheduled auctions and brings back the rooms of running auctions after a
+// restart.
+func (api *Api) RunAuctionScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		api.openStartedAuctions(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (api *Api) openStartedAuctions(ctx context.Context) {
+	products, err := api.ProductService.ListStartedProducts(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			slog.Error("failed to list started auctions", "error", err)
+		}
+
+		return
+	}
+
+	for _, product := range products {
+		if _, ok := api.AuctionLobby.Room(product.ID); ok {
+			continue
+		}
+
+		if err := api.openAuctionRoom(product); err != nil {
+			if !errors.Is(err, services.ErrLobbyClosed) {
+				slog.Error("failed to open auction room", "product_id", product.ID, "error", err)
+			}
+
+			return
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/user"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+// twoFactorLoginWindow is how long a user has, after the password was
+// accepted, to send the second factor.
+const twoFactorLoginWindow = 5 * time.Minute
+
+// startTwoFactorLogin leaves the session of r half authenticated: it knows
+// who passed the password check but is not logged in until
+// handleLoginTwoFactor accepts a code.
+func (api *Api) startTwoFactorLogin(r *http.Request, userId uuid.UUID) error {
+	generation, err := api.UserService.SessionGeneration(r.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		return err
+	}
+
+	api.Sessions.Remove(r.Context(), "AuthenticateUserId")
+	api.Sessions.Put(r.Context(), "TwoFactorUserId", userId)
+	api.Sessions.Put(r.Context(), "SessionGeneration", generation)
+	api.Sessions.Put(r.Context(), "TwoFactorDeadline", time.Now().Add(twoFactorLoginWindow).Unix())
+	return nil
+}
+
+func (api *Api) clearTwoFactorLogin(r *http.Request) {
+	api.Sessions.Remove(r.Context(), "TwoFactorUserId")
+	api.Sessions.Remove(r.Context(), "TwoFactorDeadline")
+}
+
+func (api *Api) handleLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := api.Sessions.Get(r.Context(), "TwoFactorUserId").(uuid.UUID)
+	if !ok || time.Now().Unix() > api.Sessions.GetInt64(r.Context(), "TwoFactorDeadline") {
+		api.clearTwoFactorLogin(r)
+		utils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"error": "log in with your email and password first"})
+		return
+	}
+
+	// A password change or reset since the password was accepted ends the
+	// half finished login as well.
+	current, err := api.sessionIsCurrent(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	if !current {
+		api.clearTwoFactorLogin(r)
+		utils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"error": "log in with your email and password first"})
+		return
+	}
+
+	err = api.TwoFactorService.Verify(r.Context(), userId, data.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrTwoFactorLocked):
+			api.clearTwoFactorLogin(r)
+			utils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		}
+		return
+	}
+
+	api.clearTwoFactorLogin(r)
+	if err := api.logIn(r, userId); err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "logged in successfully"})
+}
+
+func (api *Api) handleGetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	status, err := api.TwoFactorService.Status(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, status)
+}
+
+func (api *Api) handleEnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	enrollment, err := api.TwoFactorService.Enroll(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrTwoFactorEnabled) {
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, enrollment)
+}
+
+func (api *Api) handleConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	codes, err := api.TwoFactorService.Confirm(r.Context(), userId, data.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrTwoFactorEnabled), errors.Is(err, services.ErrTwoFactorNotEnrolled):
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		}
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message":        "two-factor authentication enabled, keep the recovery codes somewhere safe",
+		"recovery_codes": codes,
+	})
+}
+
+func (api *Api) handleDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.TwoFactorCodeReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.TwoFactorService.Disable(r.Context(), userId, data.Code); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrTwoFactorLocked):
+			utils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrTwoFactorNotEnabled):
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		}
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "two-factor authentication disabled"})
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/gregoryAlvim/gobid/internal/services"
 	"github.com/gregoryAlvim/gobid/internal/usecase/user"
 	"github.com/gregoryAlvim/gobid/internal/utils"
@@ -18,15 +22,18 @@ func (api *Api) handleSignUpUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	id, err := api.UserService.CreateUser(r.Context(), data.UserName, data.Email, data.Password, data.Bio)
-
-	fmt.Println("id: ", id)
-	fmt.Println("err: ", err)
-
 	if err != nil {
 		if errors.Is(err, services.ErrDuplicatedEmailOrUsername) {
 			_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "email or password already exists"})
 			return
 		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	if err := api.EmailVerificationService.SendVerification(r.Context(), id); err != nil {
+		slog.Error("failed to send verification email", "user_id", id, "error", err)
 	}
 
 	_ = utils.EncodeJson(w, r, http.StatusCreated, map[string]any{"user_id": id})
@@ -50,16 +57,51 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = api.Sessions.RenewToken(r.Context())
+	twoFactor, err := api.TwoFactorService.IsEnabled(r.Context(), id)
 	if err != nil {
 		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
 		return
 	}
 
-	api.Sessions.Put(r.Context(), "AuthenticateUserId", id)
+	if twoFactor {
+		if err := api.startTwoFactorLogin(r, id); err != nil {
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "enter the code from your authenticator app", "two_factor_required": true})
+		return
+	}
+
+	if err := api.logIn(r, id); err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
 	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "logged in successfully"})
 }
 
+// logIn makes the session of r belong to userId, under a new token and the
+// current session generation of the user.
+func (api *Api) logIn(r *http.Request, userId uuid.UUID) error {
+	generation, err := api.UserService.SessionGeneration(r.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		return err
+	}
+
+	if err := api.UserService.RecordOrigin(r.Context(), userId, api.requestOrigin(r)); err != nil {
+		slog.Error("failed to record login origin", "user_id", userId, "error", err)
+	}
+
+	api.Sessions.Put(r.Context(), "AuthenticateUserId", userId)
+	api.Sessions.Put(r.Context(), "SessionGeneration", generation)
+	return nil
+}
+
 func (api *Api) handleLogoutUser(w http.ResponseWriter, r *http.Request) {
 	err := api.Sessions.RenewToken(r.Context())
 	if err != nil {
@@ -70,3 +112,194 @@ func (api *Api) handleLogoutUser(w http.ResponseWriter, r *http.Request) {
 	api.Sessions.Remove(r.Context(), "AuthenticateUserId")
 	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "logged out successfully"})
 }
+
+func (api *Api) handleGetUserProfile(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid user id, must be a valid uuid"})
+		return
+	}
+
+	profile, err := api.UserService.GetPublicProfile(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			utils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "user not found"})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, profile)
+}
+
+func (api *Api) handleGetMyProfile(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	profile, err := api.UserService.GetProfile(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, profile)
+}
+
+func (api *Api) handleUpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.UpdateProfileReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	changes := services.ProfileChanges{
+		UserName: data.UserName,
+		Bio:      data.Bio,
+	}
+
+	profile, err := api.UserService.UpdateProfile(r.Context(), userId, changes)
+	if err != nil {
+		if errors.Is(err, services.ErrDuplicatedEmailOrUsername) {
+			utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "user name already exists"})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, profile)
+}
+
+func (api *Api) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.ChangePasswordReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	generation, err := api.UserService.ChangePassword(r.Context(), userId, data.CurrentPassword, data.NewPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "current password is incorrect"})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	// Every other session of the user, possibly opened with the old
+	// password, ended with the old generation. The current one gets a fresh
+	// token and the new generation, so it stays logged in.
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	api.Sessions.Put(r.Context(), "SessionGeneration", generation)
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "password changed successfully"})
+}
+
+func (api *Api) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.VerifyEmailReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if _, err := api.EmailVerificationService.VerifyEmail(r.Context(), data.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "email verified successfully"})
+}
+
+func (api *Api) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.EmailVerificationService.SendVerification(r.Context(), userId); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			utils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+			return
+		}
+
+		slog.Error("failed to send verification email", "user_id", userId, "error", err)
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "could not send the verification email, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "verification email sent"})
+}
+
+const passwordResetEmailTimeout = 30 * time.Second
+
+func (api *Api) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.ForgotPasswordReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	// The email is looked up and sent in the background, so that neither the
+	// answer nor how long it takes tells whether the address is registered.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), passwordResetEmailTimeout)
+		defer cancel()
+
+		if err := api.PasswordResetService.RequestReset(ctx, data.Email); err != nil {
+			slog.Error("failed to send password reset email", "error", err)
+		}
+	}()
+
+	utils.EncodeJson(w, r, http.StatusAccepted, map[string]any{"message": "if the email is registered, a link to reset the password was sent to it"})
+}
+
+func (api *Api) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := utils.DecodeValidJson[user.ResetPasswordReq](r)
+	if err != nil {
+		_ = utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	_, err = api.PasswordResetService.ResetPassword(r.Context(), data.Token, data.NewPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			utils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected internal server error"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "password reset successfully, log in with the new password"})
+}
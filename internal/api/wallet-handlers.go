@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/services"
+	"github.com/gregoryAlvim/gobid/internal/usecase/wallet"
+	"github.com/gregoryAlvim/gobid/internal/utils"
+)
+
+func (api *Api) handleGetWallet(w http.ResponseWriter, r *http.Request) {
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	wallet, err := api.WalletService.GetWallet(r.Context(), userId)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusOK, wallet)
+}
+
+func (api *Api) handleDeposit(w http.ResponseWriter, r *http.Request) {
+	api.moveFunds(w, r, true)
+}
+
+func (api *Api) handleWithdraw(w http.ResponseWriter, r *http.Request) {
+	api.moveFunds(w, r, false)
+}
+
+func (api *Api) moveFunds(w http.ResponseWriter, r *http.Request, deposit bool) {
+	data, problems, err := utils.DecodeValidJson[wallet.MoveFundsReq](r)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := authenticatedUserId(r)
+	if !ok {
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	amount, err := money.Parse(data.Amount.String(), data.Currency)
+	if err != nil {
+		utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"amount": err.Error()})
+		return
+	}
+
+	if deposit {
+		transaction, err := api.WalletService.Deposit(r.Context(), userId, amount)
+		if err != nil {
+			utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "could not deposit, try again later"})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusCreated, map[string]any{"transaction_id": transaction.ID, "amount": amount})
+		return
+	}
+
+	transaction, err := api.WalletService.Withdraw(r.Context(), userId, amount)
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientFunds) {
+			utils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "insufficient available funds for this withdrawal", "code": services.ErrCodeInsufficientFunds})
+			return
+		}
+
+		utils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "could not withdraw, try again later"})
+		return
+	}
+
+	utils.EncodeJson(w, r, http.StatusCreated, map[string]any{"transaction_id": transaction.ID, "amount": amount})
+}
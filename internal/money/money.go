@@ -0,0 +1,171 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrTooManyDecimals     = errors.New("amount has too many decimal places for its currency")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrCurrencyMismatch    = errors.New("amounts have different currencies")
+	ErrOverflow            = errors.New("amount is too large")
+)
+
+// exponents maps the supported ISO 4217 codes to the number of minor units
+// in one major unit, as a power of ten.
+var exponents = map[string]int{
+	"BRL": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"ARS": 2,
+	"CLP": 0,
+	"JPY": 0,
+}
+
+// Money is an exact monetary value stored as an integer number of minor
+// units (cents for BRL) together with its ISO 4217 currency code.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func IsSupportedCurrency(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Parse reads a decimal string such as "99.88" in the given currency. It
+// rejects amounts with more decimal places than the currency allows, apart
+// from trailing zeros, and never goes through a float.
+func Parse(amount, currency string) (Money, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return Money{}, ErrUnsupportedCurrency
+	}
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, ErrInvalidAmount
+	}
+
+	trimmed := strings.TrimRight(frac, "0")
+	if len(trimmed) > exp {
+		return Money{}, ErrTooManyDecimals
+	}
+
+	digits := whole + trimmed + strings.Repeat("0", exp-len(trimmed))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+			return Money{}, ErrOverflow
+		}
+
+		return Money{}, ErrInvalidAmount
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Mul multiplies the amount by n, e.g. a unit price by a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	product := m.Amount * n
+	if m.Amount != 0 && (product/m.Amount != n || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64)) {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// PercentCeil returns percent of the amount, rounded up to a whole minor
+// unit, so a share of a positive amount is never zero.
+func (m Money) PercentCeil(percent int64) (Money, error) {
+	scaled, err := m.Mul(percent)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount := scaled.Amount / 100
+	if scaled.Amount%100 > 0 {
+		amount++
+	}
+
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// String formats the amount in major units, e.g. "99.88", without the
+// currency code.
+func (m Money) String() string {
+	exp := exponents[m.Currency]
+
+	sign := ""
+	abs := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		abs = uint64(-(m.Amount + 1)) + 1
+	}
+
+	if exp == 0 {
+		return sign + strconv.FormatUint(abs, 10)
+	}
+
+	scale := uint64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/scale, exp, abs%scale)
+}
+
+type jsonMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.String(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw jsonMoney
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := Parse(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
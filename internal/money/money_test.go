@@ -0,0 +1,156 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+		err      error
+	}{
+		{"99.88", "BRL", 9988, nil},
+		{"99", "BRL", 9900, nil},
+		{"99.8", "USD", 9980, nil},
+		{"0.01", "EUR", 1, nil},
+		{" 10.50 ", "GBP", 1050, nil},
+		{"10.500", "BRL", 1050, nil},
+		{"-3.25", "BRL", -325, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1500.00", "CLP", 1500, nil},
+		{"10.505", "BRL", 0, ErrTooManyDecimals},
+		{"1500.5", "JPY", 0, ErrTooManyDecimals},
+		{"", "BRL", 0, ErrInvalidAmount},
+		{".50", "BRL", 0, ErrInvalidAmount},
+		{"1,50", "BRL", 0, ErrInvalidAmount},
+		{"1e3", "BRL", 0, ErrInvalidAmount},
+		{"--1", "BRL", 0, ErrInvalidAmount},
+		{"92233720368547758.08", "BRL", 0, ErrOverflow},
+		{"10", "XYZ", 0, ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.amount, tt.currency)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %s): got error %v, want %v", tt.amount, tt.currency, err, tt.err)
+			continue
+		}
+
+		if err == nil && got != New(tt.want, tt.currency) {
+			t.Errorf("Parse(%q, %s) = %+v, want %d", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(9988, "BRL"), "99.88"},
+		{New(5, "USD"), "0.05"},
+		{New(0, "EUR"), "0.00"},
+		{New(-325, "BRL"), "-3.25"},
+		{New(-5, "BRL"), "-0.05"},
+		{New(1500, "JPY"), "1500"},
+		{New(-1500, "CLP"), "-1500"},
+		{New(math.MinInt64, "BRL"), "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	for _, m := range []Money{New(9988, "BRL"), New(-5, "USD"), New(1500, "JPY")} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded Money
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+
+		if decoded != m {
+			t.Errorf("round trip of %+v through %s gave %+v", m, data, decoded)
+		}
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount":"1.999","currency":"BRL"}`), &m); !errors.Is(err, ErrTooManyDecimals) {
+		t.Errorf("too many decimals: got error %v, want %v", err, ErrTooManyDecimals)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount":"1.5","currency":"JPY"}`), &m); !errors.Is(err, ErrTooManyDecimals) {
+		t.Errorf("decimals in JPY: got error %v, want %v", err, ErrTooManyDecimals)
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		amount int64
+		n      int64
+		want   int64
+		err    error
+	}{
+		{1050, 3, 3150, nil},
+		{1050, 0, 0, nil},
+		{0, math.MaxInt64, 0, nil},
+		{-2, 4, -8, nil},
+		{math.MaxInt64, 2, 0, ErrOverflow},
+		{math.MaxInt64 / 2, 3, 0, ErrOverflow},
+		{math.MinInt64, -1, 0, ErrOverflow},
+		{-1, math.MinInt64, 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := New(tt.amount, "BRL").Mul(tt.n)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%d * %d: got error %v, want %v", tt.amount, tt.n, err, tt.err)
+			continue
+		}
+
+		if err == nil && got.Amount != tt.want {
+			t.Errorf("%d * %d = %d, want %d", tt.amount, tt.n, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestPercentCeil(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		percent int64
+		want    int64
+	}{
+		{10000, 20, 2000},
+		{999, 10, 100},
+		{1, 1, 1},
+		{1050, 0, 0},
+		{1050, 100, 1050},
+	}
+
+	for _, tt := range tests {
+		got, err := New(tt.amount, "BRL").PercentCeil(tt.percent)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Amount != tt.want {
+			t.Errorf("%d%% of %d = %d, want %d", tt.percent, tt.amount, got.Amount, tt.want)
+		}
+	}
+
+	if _, err := New(math.MaxInt64, "BRL").PercentCeil(50); !errors.Is(err, ErrOverflow) {
+		t.Errorf("got error %v, want %v", err, ErrOverflow)
+	}
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gregoryAlvim/gobid/internal/mailer"
+)
+
+// EmailNotifier sends notifications to the user's email address.
+type EmailNotifier struct {
+	mailer mailer.Mailer
+}
+
+func NewEmailNotifier(m mailer.Mailer) *EmailNotifier {
+	return &EmailNotifier{mailer: m}
+}
+
+func (en *EmailNotifier) Notify(ctx context.Context, n Notification) error {
+	if n.Email == "" {
+		return nil
+	}
+
+	return en.mailer.Send(ctx, mailer.Message{
+		To:      n.Email,
+		Subject: n.Subject,
+		Body:    fmt.Sprintf("Hi %s,\n\n%s\n", n.UserName, n.Body),
+	})
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InboxNotifier stores notifications in the notifications table, where the
+// user reads them from the app.
+type InboxNotifier struct {
+	queries *pgstore.Queries
+}
+
+func NewInboxNotifier(pool *pgxpool.Pool) *InboxNotifier {
+	return &InboxNotifier{queries: pgstore.New(pool)}
+}
+
+func (in *InboxNotifier) Notify(ctx context.Context, n Notification) error {
+	_, err := in.queries.CreateNotification(ctx, pgstore.CreateNotificationParams{
+		UserID:    n.UserID,
+		ProductID: pgtype.UUID{Bytes: n.ProductID, Valid: n.ProductID != uuid.Nil},
+		Kind:      n.Kind,
+		Subject:   n.Subject,
+		Body:      n.Body,
+	})
+	return err
+}
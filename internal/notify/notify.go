@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	KindAuctionEndingSoon = "auction_ending_soon"
+	KindAuctionEnded      = "auction_ended"
+)
+
+// Notification is a message for a single user. Email and UserName are only
+// needed by notifiers that reach the user outside of the app.
+type Notification struct {
+	UserID    uuid.UUID
+	Email     string
+	UserName  string
+	ProductID uuid.UUID
+	Kind      string
+	Subject   string
+	Body      string
+}
+
+// Notifier delivers notifications through one channel, such as the in-app
+// inbox or email.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Multi delivers every notification through all of its notifiers. A
+// failing notifier does not keep the others from running.
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Scopes a personal access token can be granted. Read covers every GET
+// request; bid and sell cover placing bids and managing products.
+const (
+	ScopeRead = "read"
+	ScopeBid  = "bid"
+	ScopeSell = "sell"
+)
+
+// AccessTokenPrefix starts every personal access token, so leaked tokens
+// are easy to recognize.
+const AccessTokenPrefix = "gobid_pat_"
+
+var (
+	ErrInvalidAccessToken  = errors.New("invalid or expired access token")
+	ErrAccessTokenNotFound = errors.New("access token not found")
+	ErrDuplicatedTokenName = errors.New("an access token with this name already exists")
+)
+
+type AccessTokenService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewAccessTokenService(pool *pgxpool.Pool) AccessTokenService {
+	return AccessTokenService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// AccessToken describes a personal access token without its secret.
+type AccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TokenIdentity is who a bearer token authenticates and what it allows.
+type TokenIdentity struct {
+	TokenID uuid.UUID
+	UserID  uuid.UUID
+	Scopes  []string
+}
+
+func (ti TokenIdentity) HasScope(scope string) bool {
+	return slices.Contains(ti.Scopes, scope)
+}
+
+// CreateToken issues a new token for userId. The token is returned only
+// here; what is stored is its SHA-256.
+func (ats *AccessTokenService) CreateToken(ctx context.Context, userId uuid.UUID, name string, scopes []string, expiresAt time.Time) (AccessToken, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return AccessToken{}, "", err
+	}
+
+	token := AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	args := pgstore.CreateAccessTokenParams{
+		UserID:    userId,
+		Name:      name,
+		TokenHash: hashAccessToken(token),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+
+	created, err := ats.queries.CreateAccessToken(ctx, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return AccessToken{}, "", ErrDuplicatedTokenName
+		}
+
+		return AccessToken{}, "", err
+	}
+
+	return accessTokenView(created), token, nil
+}
+
+func (ats *AccessTokenService) ListTokens(ctx context.Context, userId uuid.UUID) ([]AccessToken, error) {
+	rows, err := ats.queries.ListAccessTokens(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]AccessToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = accessTokenView(row)
+	}
+
+	return tokens, nil
+}
+
+func (ats *AccessTokenService) RevokeToken(ctx context.Context, userId, tokenId uuid.UUID) error {
+	args := pgstore.DeleteAccessTokenParams{
+		ID:     tokenId,
+		UserID: userId,
+	}
+
+	deleted, err := ats.queries.DeleteAccessToken(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrAccessTokenNotFound
+	}
+
+	return nil
+}
+
+// Authenticate resolves a bearer token and records that it was used.
+func (ats *AccessTokenService) Authenticate(ctx context.Context, token string) (TokenIdentity, error) {
+	if !strings.HasPrefix(token, AccessTokenPrefix) {
+		return TokenIdentity{}, ErrInvalidAccessToken
+	}
+
+	row, err := ats.queries.GetAccessTokenByHash(ctx, hashAccessToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TokenIdentity{}, ErrInvalidAccessToken
+		}
+
+		return TokenIdentity{}, err
+	}
+
+	if err := ats.queries.TouchAccessToken(ctx, row.ID); err != nil {
+		slog.Error("failed to record access token use", "token_id", row.ID, "error", err)
+	}
+
+	return TokenIdentity{
+		TokenID: row.ID,
+		UserID:  row.UserID,
+		Scopes:  row.Scopes,
+	}, nil
+}
+
+func accessTokenView(row pgstore.AccessToken) AccessToken {
+	token := AccessToken{
+		ID:        row.ID,
+		Name:      row.Name,
+		Scopes:    row.Scopes,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}
+
+	if row.LastUsedAt.Valid {
+		token.LastUsedAt = &row.LastUsedAt.Time
+	}
+
+	return token
+}
+
+func hashAccessToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
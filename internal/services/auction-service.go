@@ -2,13 +2,20 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/gregoryAlvim/gobid/internal/usecase/chat"
 )
 
 type MessageKind int
@@ -27,101 +34,788 @@ const (
 	// Infos
 	NewBidPlaced
 	AuctionFinished
+
+	// Lobby requests
+	Subscribe
+	Unsubscribe
+
+	// Lobby success
+	Subscribed
+	Unsubscribed
+
+	// Lobby errors
+	FailedToSubscribe
+	NotSubscribed
+
+	// Chat requests
+	SendChatMessage
+	DeleteChatMessage
+	MuteUser
+
+	// Chat errors
+	FailedToSendChatMessage
+	FailedToModerateChat
+
+	// Chat infos
+	NewChatMessage
+	ChatMessageDeleted
+	UserMuted
+
+	// Retraction infos
+	PriceCorrected
+
+	// Lifecycle infos
+	ServerRestarting
+
+	// Catalog infos
+	ProductUpdated
+	AuctionCancelled
+
+	// Multi-unit infos
+	ClearingPriceUpdated
 )
 
 type Message struct {
-	Message string      `json:"message,omitempty"`
-	Amount  float64     `json:"amount,omitempty"`
-	Kind    MessageKind `json:"kind"`
-	UserID  uuid.UUID   `json:"user_id,omitempty"`
+	Message      string      `json:"message,omitempty"`
+	Amount       json.Number `json:"amount,omitempty"`
+	Currency     string      `json:"currency,omitempty"`
+	Quantity     int32       `json:"quantity,omitempty"`
+	Kind         MessageKind `json:"kind"`
+	UserID       uuid.UUID   `json:"user_id,omitzero"`
+	Bidder       string      `json:"bidder,omitempty"`
+	Code         string      `json:"code,omitempty"`
+	ProductID    uuid.UUID   `json:"product_id,omitzero"`
+	MessageID    uuid.UUID   `json:"message_id,omitzero"`
+	TargetUserID uuid.UUID   `json:"target_user_id,omitzero"`
+	CreatedAt    time.Time   `json:"created_at,omitzero"`
+	AuctionEnd   time.Time   `json:"auction_end,omitzero"`
+
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// from is the connection a client message was read from.
+	from *Client
 }
 
+var ErrLobbyClosed = errors.New("the server is shutting down and is not accepting new auctions")
+
 type AuctionLobby struct {
 	sync.Mutex
 	Rooms map[uuid.UUID]*AuctionRoom
+
+	clients map[*Client]struct{}
+	closing bool
+	running sync.WaitGroup
+	writers sync.WaitGroup
+}
+
+func NewAuctionLobby() *AuctionLobby {
+	return &AuctionLobby{
+		Rooms:   make(map[uuid.UUID]*AuctionRoom),
+		clients: make(map[*Client]struct{}),
+	}
+}
+
+func (al *AuctionLobby) Room(productId uuid.UUID) (*AuctionRoom, bool) {
+	al.Lock()
+	defer al.Unlock()
+
+	room, ok := al.Rooms[productId]
+	return room, ok
+}
+
+// Accepting reports whether the lobby still takes new rooms and sockets.
+func (al *AuctionLobby) Accepting() bool {
+	al.Lock()
+	defer al.Unlock()
+
+	return !al.closing
+}
+
+// Open registers the room and starts running it. The room removes itself
+// from the lobby once the auction is over. Opening a room for an auction
+// that already has one does nothing.
+func (al *AuctionLobby) Open(room *AuctionRoom) error {
+	al.Lock()
+	defer al.Unlock()
+
+	if al.closing {
+		return ErrLobbyClosed
+	}
+
+	if _, ok := al.Rooms[room.Id]; ok {
+		return nil
+	}
+
+	room.lobby = al
+	al.Rooms[room.Id] = room
+	al.running.Add(1)
+
+	go func() {
+		defer al.running.Done()
+		room.Run()
+	}()
+
+	return nil
+}
+
+func (al *AuctionLobby) remove(room *AuctionRoom) {
+	al.Lock()
+	defer al.Unlock()
+
+	if current, ok := al.Rooms[room.Id]; ok && current == room {
+		delete(al.Rooms, room.Id)
+	}
+}
+
+func (al *AuctionLobby) connect(c *Client) bool {
+	al.Lock()
+	defer al.Unlock()
+
+	if al.closing {
+		return false
+	}
+
+	al.clients[c] = struct{}{}
+	al.writers.Add(1)
+	return true
+}
+
+func (al *AuctionLobby) disconnect(c *Client) {
+	al.Lock()
+	defer al.Unlock()
+
+	delete(al.clients, c)
+}
+
+// Shutdown stops accepting rooms and sockets, lets every room finish the
+// bids already submitted to it and then asks all connected clients to
+// reconnect later. It returns once every socket has written its close
+// frame, or when ctx is done, closing the sockets that are left.
+func (al *AuctionLobby) Shutdown(ctx context.Context) error {
+	al.Lock()
+	al.closing = true
+	rooms := make([]*AuctionRoom, 0, len(al.Rooms))
+	for _, room := range al.Rooms {
+		rooms = append(rooms, room)
+	}
+	al.Unlock()
+
+	for _, room := range rooms {
+		room.stop()
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		al.running.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	al.Lock()
+	clients := make([]*Client, 0, len(al.clients))
+	for c := range al.clients {
+		clients = append(clients, c)
+	}
+	al.Unlock()
+
+	for _, c := range clients {
+		// A client too slow to take the message would keep its write
+		// loop waiting, so it is dropped right away.
+		if !c.deliver(Message{Kind: ServerRestarting, Message: "server restarting, reconnect"}) {
+			c.Conn.Close()
+		}
+	}
+
+	written := make(chan struct{})
+	go func() {
+		al.writers.Wait()
+		close(written)
+	}()
+
+	select {
+	case <-written:
+	case <-ctx.Done():
+		for _, c := range clients {
+			c.Conn.Close()
+		}
+
+		return ctx.Err()
+	}
+
+	return err
 }
 
 type AuctionRoom struct {
 	Id          uuid.UUID
+	SellerId    uuid.UUID
+	AuctionEnd  time.Time
+	Currency    string
+	Quantity    int32
 	Context     context.Context
 	Broadcast   chan Message
 	Register    chan *Client
 	Unregister  chan *Client
-	Clients     map[uuid.UUID]*Client
+	Clients     map[*Client]struct{}
 	BidsService BidsService
+	ChatService ChatService
+
+	IdempotencyService IdempotencyService
+
+	lobby        *AuctionLobby
+	timer        *time.Timer
+	done         chan struct{}
+	shutdown     chan struct{}
+	stopOnce     sync.Once
+	muted        map[uuid.UUID]bool
+	chatActivity map[uuid.UUID][]time.Time
+	pseudonyms   map[uuid.UUID]string
 }
 
-func NewAuctionRoom(ctx context.Context, id uuid.UUID, bidsService BidsService) *AuctionRoom {
+func NewAuctionRoom(ctx context.Context, product pgstore.Product, bidsService BidsService, chatService ChatService, idempotencyService IdempotencyService) *AuctionRoom {
 	return &AuctionRoom{
-		Id:          id,
+		Id:          product.ID,
+		SellerId:    product.SellerID,
+		AuctionEnd:  product.AuctionEnd,
+		Currency:    product.Currency,
+		Quantity:    product.Quantity,
 		Context:     ctx,
 		Broadcast:   make(chan Message),
 		Register:    make(chan *Client),
 		Unregister:  make(chan *Client),
-		Clients:     make(map[uuid.UUID]*Client),
+		Clients:     make(map[*Client]struct{}),
 		BidsService: bidsService,
+		ChatService: chatService,
+
+		IdempotencyService: idempotencyService,
+
+		done:         make(chan struct{}),
+		shutdown:     make(chan struct{}),
+		muted:        make(map[uuid.UUID]bool),
+		chatActivity: make(map[uuid.UUID][]time.Time),
+		pseudonyms:   make(map[uuid.UUID]string),
+	}
+}
+
+// Done is closed once the room has stopped running, either because the
+// auction ended or because the server is shutting down.
+func (ar *AuctionRoom) Done() <-chan struct{} {
+	return ar.done
+}
+
+func (ar *AuctionRoom) stop() {
+	ar.stopOnce.Do(func() { close(ar.shutdown) })
+}
+
+func (ar *AuctionRoom) join(c *Client) bool {
+	select {
+	case ar.Register <- c:
+		return true
+	case <-ar.done:
+		return false
+	}
+}
+
+func (ar *AuctionRoom) leave(c *Client) {
+	select {
+	case ar.Unregister <- c:
+	case <-ar.done:
+	}
+}
+
+func (ar *AuctionRoom) submit(m Message) bool {
+	select {
+	case ar.Broadcast <- m:
+		return true
+	case <-ar.done:
+		return false
 	}
 }
 
 func (ar *AuctionRoom) registerClient(c *Client) {
 	slog.Info("new user connected", "client", c)
-	ar.Clients[c.UserId] = c
+	ar.Clients[c] = struct{}{}
+
+	history, err := ar.ChatService.GetHistory(ar.Context, ar.Id)
+	if err != nil {
+		slog.Error("failed to load chat history", "room_id", ar.Id, "error", err)
+		return
+	}
+
+	for _, message := range history {
+		ar.send(c, Message{
+			Kind:      NewChatMessage,
+			Message:   message.Content,
+			MessageID: message.ID,
+			UserID:    message.SenderID,
+			Bidder:    ar.pseudonym(message.SenderID),
+			CreatedAt: message.CreatedAt,
+		})
+	}
 }
 
 func (ar *AuctionRoom) unregisterClient(c *Client) {
 	slog.Info("user disconnected", "client", c)
-	delete(ar.Clients, c.UserId)
+	delete(ar.Clients, c)
+}
+
+// send tags the message with the room's product so clients following
+// several auctions over the lobby socket can tell the events apart.
+func (ar *AuctionRoom) send(c *Client, m Message) {
+	m.ProductID = ar.Id
+	c.deliver(redact(c, m))
+}
+
+// redact hides who is behind the pseudonym of an event from everyone but
+// that user and admins.
+func redact(c *Client, m Message) Message {
+	if m.Bidder == "" || c.IsAdmin {
+		return m
+	}
+
+	if m.UserID != c.UserId {
+		m.UserID = uuid.Nil
+	}
+
+	if m.TargetUserID != c.UserId {
+		m.TargetUserID = uuid.Nil
+	}
+
+	return m
+}
+
+// pseudonym returns the name a participant goes by in the room events. The
+// seller is public anyway and is simply called "Seller".
+func (ar *AuctionRoom) pseudonym(userId uuid.UUID) string {
+	if userId == ar.SellerId {
+		return "Seller"
+	}
+
+	if pseudonym, ok := ar.pseudonyms[userId]; ok {
+		return pseudonym
+	}
+
+	pseudonym, err := ar.BidsService.Pseudonym(ar.Context, ar.Id, userId)
+	if err != nil {
+		slog.Error("failed to assign pseudonym", "room_id", ar.Id, "user_id", userId, "error", err)
+		return "Anonymous"
+	}
+
+	ar.pseudonyms[userId] = pseudonym
+	return pseudonym
 }
 
 func (ar *AuctionRoom) broadcastMessage(m Message) {
 	slog.Info("new message received", "room_id", ar.Id, "message", m, "user_id", m.UserID)
 	switch m.Kind {
 	case PlaceBid:
-		bid, err := ar.BidsService.PlaceBid(ar.Context, ar.Id, m.UserID, m.Amount)
+		ar.placeBid(m)
+
+	case NewBidPlaced:
+		ar.announceBid(m)
+
+	case SendChatMessage:
+		ar.sendChatMessage(m)
+
+	case DeleteChatMessage:
+		ar.deleteChatMessage(m)
+
+	case MuteUser:
+		ar.muteUser(m)
+
+	case PriceCorrected:
+		for client := range ar.Clients {
+			ar.send(client, m)
+		}
+
+		ar.announceClearingPrice()
+
+	case ProductUpdated:
+		ar.AuctionEnd = m.AuctionEnd
+		ar.Currency = m.Currency
+		ar.timer.Reset(time.Until(ar.AuctionEnd))
+
+		for client := range ar.Clients {
+			ar.send(client, m)
+		}
+
+	case InvalidJson:
+		ar.reply(m.UserID, m)
+	}
+}
+
+// reply sends a message meant for one user to every connection they have
+// in the room, so all of their tabs and sockets stay in sync.
+func (ar *AuctionRoom) reply(userId uuid.UUID, m Message) {
+	for client := range ar.Clients {
+		if client.UserId == userId {
+			ar.send(client, m)
+		}
+	}
+}
+
+// sender returns the connection a client message came from, as long as it
+// is still in the room.
+func (ar *AuctionRoom) sender(m Message) (*Client, bool) {
+	if m.from == nil {
+		return nil, false
+	}
+
+	_, ok := ar.Clients[m.from]
+	return m.from, ok
+}
+
+// placeBid handles a bid sent over the websocket. Bids carrying an
+// idempotency key are only processed once; repeating the key replays the
+// original reply instead of bidding again.
+func (ar *AuctionRoom) placeBid(m Message) {
+	scope := "ws_bid:" + ar.Id.String()
+
+	if m.IdempotencyKey != "" {
+		requestHash := HashIdempotentRequest(m.Amount.String(), m.Currency, strconv.Itoa(int(m.Quantity)))
+
+		result, replay, err := ar.IdempotencyService.Begin(ar.Context, m.UserID, scope, m.IdempotencyKey, requestHash)
 		if err != nil {
-			if errors.Is(err, ErrBidTooLow) {
-				if client, ok := ar.Clients[m.UserID]; ok {
-					client.Send <- Message{Kind: FailedToPlaceBid, Message: ErrBidTooLow.Error(), UserID: m.UserID}
-				}
+			message := "could not place your bid, try again later"
+			if errors.Is(err, ErrIdempotencyKeyInProgress) || errors.Is(err, ErrIdempotencyKeyMismatch) {
+				message = err.Error()
+			} else {
+				slog.Error("failed to claim idempotency key", "room_id", ar.Id, "user_id", m.UserID, "error", err)
+			}
+
+			ar.reply(m.UserID, Message{Kind: FailedToPlaceBid, Message: message, UserID: m.UserID, IdempotencyKey: m.IdempotencyKey})
+			return
+		}
+
+		if replay {
+			var original Message
+			if err := json.Unmarshal(result.Body, &original); err != nil {
+				slog.Error("failed to decode stored bid reply", "room_id", ar.Id, "error", err)
 				return
 			}
+
+			ar.reply(m.UserID, original)
+			return
 		}
+	}
+
+	reply, bid, err := ar.submitBid(m)
+	reply.IdempotencyKey = m.IdempotencyKey
 
-		if client, ok := ar.Clients[m.UserID]; ok {
-			client.Send <- Message{Kind: SuccessfullyPlacedBid, Message: "Your bid was Successfully placed.", UserID: m.UserID}
+	if m.IdempotencyKey != "" {
+		if err != nil {
+			err = ar.IdempotencyService.Release(ar.Context, m.UserID, scope, m.IdempotencyKey)
+		} else {
+			err = ar.IdempotencyService.Complete(ar.Context, m.UserID, scope, m.IdempotencyKey, http.StatusOK, reply)
 		}
 
-		for id, client := range ar.Clients {
-			newBidMessage := Message{
-				Kind:    NewBidPlaced,
-				Message: "A new bid was placed", Amount: bid.BidAmount,
-				UserID: m.UserID,
-			}
+		if err != nil {
+			slog.Error("failed to store idempotency key", "room_id", ar.Id, "user_id", m.UserID, "error", err)
+		}
+	}
 
-			if id == m.UserID {
-				continue
-			}
+	ar.reply(m.UserID, reply)
+
+	if bid != nil {
+		ar.announceBid(ar.newBidMessage(*bid))
+	}
+}
+
+// submitBid places the bid and builds the reply for the bidder. The error is
+// only set for unexpected failures, never for rejected bids.
+func (ar *AuctionRoom) submitBid(m Message) (Message, *pgstore.Bid, error) {
+	amount, err := money.Parse(m.Amount.String(), ar.Currency)
+	if err != nil {
+		return Message{Kind: FailedToPlaceBid, Message: err.Error(), UserID: m.UserID}, nil, nil
+	}
+
+	var origin RequestOrigin
+	if m.from != nil {
+		origin = m.from.Origin
+	}
+
+	quantity := m.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
 
-			client.Send <- newBidMessage
+	bid, err := ar.BidsService.PlaceBid(ar.Context, ar.Id, m.UserID, amount, quantity, origin)
+	if err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return Message{Kind: FailedToPlaceBid, Message: err.Error(), Code: ErrCodeInsufficientFunds, UserID: m.UserID}, nil, nil
 		}
 
-	case InvalidJson:
-		client, ok := ar.Clients[m.UserID]
-		if !ok {
-			slog.Info("client not found", "user_id", m.UserID)
+		if errors.Is(err, ErrEmailNotVerified) {
+			return Message{Kind: FailedToPlaceBid, Message: err.Error(), Code: ErrCodeEmailNotVerified, UserID: m.UserID}, nil, nil
+		}
+
+		if errors.Is(err, ErrBidTooLow) || errors.Is(err, ErrAuctionClosed) || errors.Is(err, ErrAuctionNotStarted) || errors.Is(err, ErrSellerCannotBid) || errors.Is(err, ErrProductNotFound) || errors.Is(err, money.ErrCurrencyMismatch) || errors.Is(err, money.ErrOverflow) || errors.Is(err, ErrInvalidBidQuantity) {
+			return Message{Kind: FailedToPlaceBid, Message: err.Error(), UserID: m.UserID}, nil, nil
+		}
+
+		slog.Error("failed to place bid", "room_id", ar.Id, "user_id", m.UserID, "error", err)
+		return Message{Kind: FailedToPlaceBid, Message: "could not place your bid, try again later", UserID: m.UserID}, nil, err
+	}
+
+	return Message{Kind: SuccessfullyPlacedBid, Message: "Your bid was Successfully placed.", UserID: m.UserID}, &bid, nil
+}
+
+func (ar *AuctionRoom) newBidMessage(bid pgstore.Bid) Message {
+	price := money.New(bid.BidAmount, ar.Currency)
+	return Message{
+		Kind:     NewBidPlaced,
+		Message:  "A new bid was placed",
+		Amount:   json.Number(price.String()),
+		Currency: price.Currency,
+		Quantity: bid.Quantity,
+		UserID:   bid.BidderID,
+	}
+}
+
+// announceBid tells everyone but the bidder about a new bid.
+func (ar *AuctionRoom) announceBid(m Message) {
+	m.Bidder = ar.pseudonym(m.UserID)
+
+	for client := range ar.Clients {
+		if client.UserId == m.UserID {
+			continue
+		}
+
+		ar.send(client, m)
+	}
+
+	ar.announceClearingPrice()
+}
+
+// announceClearingPrice tells everyone in a multi-unit room the lowest
+// amount that currently wins a unit and how many units are taken.
+func (ar *AuctionRoom) announceClearingPrice() {
+	if ar.Quantity <= 1 {
+		return
+	}
+
+	book, err := ar.BidsService.Book(ar.Context, ar.Id)
+	if err != nil {
+		slog.Error("failed to load the clearing price", "room_id", ar.Id, "error", err)
+		return
+	}
+
+	m := Message{
+		Kind:     ClearingPriceUpdated,
+		Message:  fmt.Sprintf("%d of %d units are taken", book.UnitsAllocated, book.Quantity),
+		Amount:   json.Number(book.ClearingPrice.String()),
+		Currency: book.ClearingPrice.Currency,
+		Quantity: book.UnitsAllocated,
+	}
+
+	for client := range ar.Clients {
+		ar.send(client, m)
+	}
+}
+
+// AnnounceBid publishes a bid placed outside of the websocket, e.g. through
+// the REST API, to the clients of the room.
+func (ar *AuctionRoom) AnnounceBid(bid pgstore.Bid) {
+	ar.Notify(ar.newBidMessage(bid))
+}
+
+// Notify pushes a server side event, such as a price correction, into the
+// room. It gives up once the room has stopped running.
+func (ar *AuctionRoom) Notify(m Message) {
+	ar.submit(m)
+}
+
+// UpdateProduct tells the room and its clients that the seller changed the
+// product, moving the end of the auction if needed.
+func (ar *AuctionRoom) UpdateProduct(product pgstore.Product) {
+	basePrice := money.New(product.BasePrice, product.Currency)
+	ar.Notify(Message{
+		Kind:       ProductUpdated,
+		Message:    "The seller updated this product",
+		Amount:     json.Number(basePrice.String()),
+		Currency:   basePrice.Currency,
+		AuctionEnd: product.AuctionEnd,
+	})
+}
+
+// Cancel closes the room of a product the seller deleted.
+func (ar *AuctionRoom) Cancel() {
+	ar.Notify(Message{Kind: AuctionCancelled, Message: "The seller removed this product, the auction was cancelled"})
+}
+
+const (
+	chatRateLimit  = 5
+	chatRateWindow = 10 * time.Second
+)
+
+// allowChatMessage enforces a sliding window rate limit of chatRateLimit
+// messages per chatRateWindow for each user of the room.
+func (ar *AuctionRoom) allowChatMessage(userId uuid.UUID, now time.Time) bool {
+	recent := ar.chatActivity[userId][:0]
+	for _, sentAt := range ar.chatActivity[userId] {
+		if now.Sub(sentAt) < chatRateWindow {
+			recent = append(recent, sentAt)
+		}
+	}
+
+	if len(recent) >= chatRateLimit {
+		ar.chatActivity[userId] = recent
+		return false
+	}
+
+	ar.chatActivity[userId] = append(recent, now)
+	return true
+}
+
+func (ar *AuctionRoom) canModerate(c *Client) bool {
+	return c.UserId == ar.SellerId || c.IsAdmin
+}
+
+func (ar *AuctionRoom) sendChatMessage(m Message) {
+	if _, ok := ar.sender(m); !ok {
+		slog.Info("client not found", "user_id", m.UserID)
+		return
+	}
+
+	if ar.muted[m.UserID] {
+		ar.reply(m.UserID, Message{Kind: FailedToSendChatMessage, Message: "you have been muted for the rest of this auction"})
+		return
+	}
+
+	req := chat.SendChatMessageReq{Content: m.Message, BlockedWords: ar.ChatService.BlockedWords()}
+	if problems := req.Valid(ar.Context); len(problems) > 0 {
+		ar.reply(m.UserID, Message{Kind: FailedToSendChatMessage, Message: problems["message"]})
+		return
+	}
+
+	if !ar.allowChatMessage(m.UserID, time.Now()) {
+		ar.reply(m.UserID, Message{Kind: FailedToSendChatMessage, Message: "you are sending messages too fast, slow down"})
+		return
+	}
+
+	message, err := ar.ChatService.SendMessage(ar.Context, ar.Id, m.UserID, m.Message)
+	if err != nil {
+		slog.Error("failed to save chat message", "room_id", ar.Id, "error", err)
+		ar.reply(m.UserID, Message{Kind: FailedToSendChatMessage, Message: "could not send your message, try again later"})
+		return
+	}
+
+	for c := range ar.Clients {
+		ar.send(c, Message{
+			Kind:      NewChatMessage,
+			Message:   message.Content,
+			MessageID: message.ID,
+			UserID:    message.SenderID,
+			Bidder:    ar.pseudonym(message.SenderID),
+			CreatedAt: message.CreatedAt,
+		})
+	}
+}
+
+func (ar *AuctionRoom) deleteChatMessage(m Message) {
+	client, ok := ar.sender(m)
+	if !ok {
+		slog.Info("client not found", "user_id", m.UserID)
+		return
+	}
+
+	if !ar.canModerate(client) {
+		ar.reply(m.UserID, Message{Kind: FailedToModerateChat, Message: "only the seller or an admin can delete messages"})
+		return
+	}
+
+	if err := ar.ChatService.DeleteMessage(ar.Context, ar.Id, m.MessageID, m.UserID); err != nil {
+		if errors.Is(err, ErrChatMessageNotFound) {
+			ar.reply(m.UserID, Message{Kind: FailedToModerateChat, Message: ErrChatMessageNotFound.Error(), MessageID: m.MessageID})
+			return
 		}
 
-		client.Send <- m
+		slog.Error("failed to delete chat message", "room_id", ar.Id, "error", err)
+		ar.reply(m.UserID, Message{Kind: FailedToModerateChat, Message: "could not delete the message, try again later", MessageID: m.MessageID})
+		return
+	}
+
+	for c := range ar.Clients {
+		ar.send(c, Message{Kind: ChatMessageDeleted, Message: "A message was removed by a moderator", MessageID: m.MessageID})
+	}
+}
+
+// muteUser silences a participant, addressed either by TargetUserID or, for
+// moderators who only see pseudonyms, by Bidder.
+func (ar *AuctionRoom) muteUser(m Message) {
+	client, ok := ar.sender(m)
+	if !ok {
+		slog.Info("client not found", "user_id", m.UserID)
+		return
+	}
+
+	if !ar.canModerate(client) {
+		ar.reply(m.UserID, Message{Kind: FailedToModerateChat, Message: "only the seller or an admin can mute users"})
+		return
+	}
+
+	if m.TargetUserID == uuid.Nil && m.Bidder != "" {
+		targetId, err := ar.BidsService.ResolvePseudonym(ar.Context, ar.Id, m.Bidder)
+		if err != nil {
+			message := "could not mute the user, try again later"
+			if errors.Is(err, ErrPseudonymNotFound) {
+				message = err.Error()
+			} else {
+				slog.Error("failed to resolve pseudonym", "room_id", ar.Id, "error", err)
+			}
+
+			ar.reply(m.UserID, Message{Kind: FailedToModerateChat, Message: message})
+			return
+		}
+
+		m.TargetUserID = targetId
+	}
+
+	if m.TargetUserID == uuid.Nil || m.TargetUserID == ar.SellerId || m.TargetUserID == m.UserID {
+		ar.reply(m.UserID, Message{Kind: FailedToModerateChat, Message: "this user cannot be muted", TargetUserID: m.TargetUserID})
+		return
+	}
+
+	ar.muted[m.TargetUserID] = true
+
+	for c := range ar.Clients {
+		ar.send(c, Message{Kind: UserMuted, Message: "A user was muted for the rest of this auction", TargetUserID: m.TargetUserID, Bidder: ar.pseudonym(m.TargetUserID)})
+	}
+}
+
+// drain handles whatever clients already managed to submit before the
+// server started shutting down, so in-flight bids are not lost.
+func (ar *AuctionRoom) drain() {
+	for {
+		select {
+		case client := <-ar.Register:
+			ar.registerClient(client)
+		case client := <-ar.Unregister:
+			ar.unregisterClient(client)
+		case message := <-ar.Broadcast:
+			ar.broadcastMessage(message)
+		default:
+			return
+		}
 	}
 }
 
 func (ar *AuctionRoom) Run() {
 	slog.Info("Auction has begun.", "auction_id", ar.Id)
 
+	ar.timer = time.NewTimer(time.Until(ar.AuctionEnd))
+
 	defer func() {
-		close(ar.Broadcast)
-		close(ar.Register)
-		close(ar.Unregister)
+		ar.timer.Stop()
+		close(ar.done)
+		if ar.lobby != nil {
+			ar.lobby.remove(ar)
+		}
 	}()
 
 	for {
@@ -131,15 +825,33 @@ func (ar *AuctionRoom) Run() {
 		case client := <-ar.Unregister:
 			ar.unregisterClient(client)
 		case message := <-ar.Broadcast:
+			if message.Kind == AuctionCancelled {
+				slog.Info("Auction was cancelled.", "auction_id", ar.Id)
+
+				for client := range ar.Clients {
+					ar.send(client, message)
+				}
+
+				return
+			}
+
 			ar.broadcastMessage(message)
-		case <-ar.Context.Done():
+		case <-ar.shutdown:
+			slog.Info("Auction room is shutting down.", "auction_id", ar.Id)
+			ar.drain()
+			return
+		case <-ar.timer.C:
 			slog.Info("Auction has ended.", "auction_id", ar.Id)
 
-			for _, client := range ar.Clients {
-				client.Send <- Message{
+			if err := ar.BidsService.FinalizeAuction(ar.Context, ar.Id); err != nil {
+				slog.Error("failed to finalize auction", "auction_id", ar.Id, "error", err)
+			}
+
+			for client := range ar.Clients {
+				ar.send(client, Message{
 					Kind:    AuctionFinished,
 					Message: "The auction has ended. Thank you for participating!",
-				}
+				})
 			}
 
 			return
@@ -147,22 +859,110 @@ func (ar *AuctionRoom) Run() {
 	}
 }
 
+// Client is a websocket connection subscribed to one or more auction rooms.
+// Clients created through NewClient follow a single room for their whole
+// life, while lobby clients (NewLobbyClient) subscribe and unsubscribe
+// from rooms on demand.
 type Client struct {
-	Room   *AuctionRoom
-	Conn   *websocket.Conn
-	Send   chan Message
-	UserId uuid.UUID
+	Lobby   *AuctionLobby
+	Conn    *websocket.Conn
+	Send    chan Message
+	UserId  uuid.UUID
+	IsAdmin bool
+	Origin  RequestOrigin
+	// ReadOnly clients follow auctions but cannot bid or chat, like
+	// connections made with an access token without the bid scope.
+	ReadOnly bool
+
+	mu          sync.Mutex
+	rooms       map[uuid.UUID]*AuctionRoom
+	multiplexed bool
 }
 
 func NewClient(room *AuctionRoom, conn *websocket.Conn, userId uuid.UUID) *Client {
 	return &Client{
-		Room:   room,
+		Lobby:  room.lobby,
 		Conn:   conn,
 		Send:   make(chan Message, 512),
 		UserId: userId,
+		rooms:  map[uuid.UUID]*AuctionRoom{room.Id: room},
+	}
+}
+
+func NewLobbyClient(lobby *AuctionLobby, conn *websocket.Conn, userId uuid.UUID) *Client {
+	return &Client{
+		Lobby:       lobby,
+		Conn:        conn,
+		Send:        make(chan Message, 512),
+		UserId:      userId,
+		rooms:       make(map[uuid.UUID]*AuctionRoom),
+		multiplexed: true,
+	}
+}
+
+// deliver queues a message without ever blocking the caller, so a slow or
+// dead connection cannot stall a room. It reports whether the message was
+// queued.
+func (c *Client) deliver(m Message) bool {
+	select {
+	case c.Send <- m:
+		return true
+	default:
+		slog.Warn("dropping message for slow client", "user_id", c.UserId, "kind", m.Kind)
+		return false
 	}
 }
 
+func (c *Client) addRoom(room *AuctionRoom) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.rooms[room.Id]; ok {
+		return false
+	}
+
+	c.rooms[room.Id] = room
+	return true
+}
+
+func (c *Client) removeRoom(productId uuid.UUID) (*AuctionRoom, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	room, ok := c.rooms[productId]
+	delete(c.rooms, productId)
+	return room, ok
+}
+
+// room resolves the room a message is addressed to. Messages without a
+// product id are accepted when the client follows exactly one room, which
+// keeps the single auction socket protocol unchanged.
+func (c *Client) room(productId uuid.UUID) (*AuctionRoom, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if productId == uuid.Nil && len(c.rooms) == 1 {
+		for _, room := range c.rooms {
+			return room, true
+		}
+	}
+
+	room, ok := c.rooms[productId]
+	return room, ok
+}
+
+func (c *Client) subscriptions() []*AuctionRoom {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rooms := make([]*AuctionRoom, 0, len(c.rooms))
+	for _, room := range c.rooms {
+		rooms = append(rooms, room)
+	}
+
+	return rooms
+}
+
 const (
 	maxMessageSize = 512
 	readDeadline   = 60 * time.Second
@@ -170,9 +970,116 @@ const (
 	pingPeriod     = (readDeadline * 9) / 10
 )
 
+func (c *Client) subscribe(productId uuid.UUID) {
+	if !c.multiplexed {
+		c.deliver(Message{Kind: FailedToSubscribe, Message: "subscriptions are only available on the lobby socket", ProductID: productId})
+		return
+	}
+
+	room, ok := c.Lobby.Room(productId)
+	if !ok {
+		c.deliver(Message{Kind: FailedToSubscribe, Message: "the auction for this product has ended or does not exist", ProductID: productId})
+		return
+	}
+
+	if c.addRoom(room) && !room.join(c) {
+		c.removeRoom(productId)
+		c.deliver(Message{Kind: FailedToSubscribe, Message: "the auction for this product has ended or does not exist", ProductID: productId})
+		return
+	}
+
+	c.deliver(Message{Kind: Subscribed, Message: "You are now following this auction.", ProductID: productId})
+}
+
+func (c *Client) unsubscribe(productId uuid.UUID) {
+	room, ok := c.removeRoom(productId)
+	if !ok {
+		c.deliver(Message{Kind: NotSubscribed, Message: "you are not following this auction", ProductID: productId})
+		return
+	}
+
+	room.leave(c)
+	c.deliver(Message{Kind: Unsubscribed, Message: "You are no longer following this auction.", ProductID: productId})
+}
+
+func (c *Client) dispatch(m Message) {
+	switch m.Kind {
+	case Subscribe:
+		c.subscribe(m.ProductID)
+	case Unsubscribe:
+		c.unsubscribe(m.ProductID)
+	case PlaceBid, SendChatMessage, DeleteChatMessage, MuteUser:
+		if c.ReadOnly {
+			c.deliver(Message{Kind: readOnlyFailure(m.Kind), Message: "this connection is read only", ProductID: m.ProductID})
+			return
+		}
+
+		room, ok := c.room(m.ProductID)
+		if !ok {
+			c.deliver(Message{Kind: NotSubscribed, Message: "you are not following this auction", ProductID: m.ProductID})
+			return
+		}
+
+		m.ProductID = room.Id
+		if !room.submit(m) {
+			c.deliver(Message{Kind: NotSubscribed, Message: "the auction for this product has ended", ProductID: m.ProductID})
+		}
+	default:
+		c.deliver(Message{Kind: InvalidJson, Message: "unsupported message kind", ProductID: m.ProductID})
+	}
+}
+
+func readOnlyFailure(kind MessageKind) MessageKind {
+	switch kind {
+	case PlaceBid:
+		return FailedToPlaceBid
+	case SendChatMessage:
+		return FailedToSendChatMessage
+	default:
+		return FailedToModerateChat
+	}
+}
+
+// Start registers the client with its lobby and rooms and spawns its read
+// and write loops. It returns false when the server is shutting down or the
+// room already finished, in which case the connection is closed.
+func (c *Client) Start() bool {
+	if c.Lobby != nil && !c.Lobby.connect(c) {
+		c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server restarting, reconnect"))
+		c.Conn.Close()
+		return false
+	}
+
+	for _, room := range c.subscriptions() {
+		if !room.join(c) {
+			c.removeRoom(room.Id)
+		}
+	}
+
+	if !c.multiplexed && len(c.subscriptions()) == 0 {
+		if c.Lobby != nil {
+			c.Lobby.disconnect(c)
+			c.Lobby.writers.Done()
+		}
+		c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "the auction has ended"))
+		c.Conn.Close()
+		return false
+	}
+
+	go c.ReadEventLoop()
+	go c.WriteEventLoop()
+
+	return true
+}
+
 func (c *Client) ReadEventLoop() {
 	defer func() {
-		c.Room.Unregister <- c
+		for _, room := range c.subscriptions() {
+			room.leave(c)
+		}
+		if c.Lobby != nil {
+			c.Lobby.disconnect(c)
+		}
 		c.Conn.Close()
 	}()
 
@@ -187,17 +1094,23 @@ func (c *Client) ReadEventLoop() {
 		var m Message
 		err := c.Conn.ReadJSON(&m)
 		if err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				slog.Warn("invalid json received from client", "user_id", c.UserId, "error", err)
+				continue
+			}
+
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				slog.Error("unexpected close error", "error", err)
-				return
 			}
 
-			slog.Warn("invalid json received from client", "user_id", c.UserId, "error", err)
-			continue
+			return
 		}
 
 		m.UserID = c.UserId
-		c.Room.Broadcast <- m
+		m.from = c
+		c.dispatch(m)
 	}
 }
 
@@ -206,6 +1119,9 @@ func (c *Client) WriteEventLoop() {
 	defer func() {
 		ticker.Stop()
 		c.Conn.Close()
+		if c.Lobby != nil {
+			c.Lobby.writers.Done()
+		}
 	}()
 
 	for {
@@ -219,14 +1135,30 @@ func (c *Client) WriteEventLoop() {
 				return
 			}
 
-			if message.Kind == AuctionFinished {
-				return
-			}
-
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			err := c.Conn.WriteJSON(message)
 			if err != nil {
-				c.Room.Unregister <- c
+				for _, room := range c.subscriptions() {
+					room.leave(c)
+				}
+				return
+			}
+
+			switch message.Kind {
+			case AuctionFinished:
+				c.removeRoom(message.ProductID)
+				if !c.multiplexed {
+					c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "the auction has ended"))
+					return
+				}
+			case AuctionCancelled:
+				c.removeRoom(message.ProductID)
+				if !c.multiplexed {
+					c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "the auction was cancelled"))
+					return
+				}
+			case ServerRestarting:
+				c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, message.Message))
 				return
 			}
 
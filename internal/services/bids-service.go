@@ -1,13 +1,22 @@
 package services
 
 import (
+	"cmp"
 	"context"
+	"encoding/base64"
 	"errors"
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
 	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,9 +32,25 @@ func NewBidsService(pool *pgxpool.Pool) BidsService {
 	}
 }
 
-var ErrBidTooLow = errors.New("bid amount is too low")
+var (
+	ErrBidTooLow          = errors.New("bid amount is too low")
+	ErrAuctionClosed      = errors.New("the auction for this product has ended")
+	ErrSellerCannotBid    = errors.New("sellers cannot bid on their own products")
+	ErrInvalidBidQuantity = errors.New("the bid quantity must be between 1 and the number of units for sale")
+	ErrAuctionNotStarted  = errors.New("the auction for this product has not started yet")
+)
 
-func (bs *BidsService) PlaceBid(ctx context.Context, product_id, bidder_id uuid.UUID, amount float64) (pgstore.Bid, error) {
+// PlaceBid accepts a bid for quantity units at amount per unit. On single
+// unit products the bid must beat the current highest bid; on multi-unit
+// products it must beat the clearing price once every unit is taken, and a
+// new bid replaces the bidder's previous one. The product row is locked for
+// the whole transaction, so bids for the same product are serialized no
+// matter where they come from; the bids_strictly_increasing trigger enforces
+// the single unit invariant in the database. The origin is stored with the
+// bid for the shill analyzer. When holds are enabled the bid must be covered
+// by the bidder's wallet, otherwise ErrInsufficientFunds is returned.
+// Bidders must have verified their email.
+func (bs *BidsService) PlaceBid(ctx context.Context, product_id, bidder_id uuid.UUID, amount money.Money, quantity int32, origin RequestOrigin) (pgstore.Bid, error) {
 	tx, err := bs.pool.Begin(ctx)
 	if err != nil {
 		return pgstore.Bid{}, err
@@ -35,47 +60,752 @@ func (bs *BidsService) PlaceBid(ctx context.Context, product_id, bidder_id uuid.
 
 	qtx := bs.queries.WithTx(tx)
 
-	product, err := qtx.GetProductById(ctx, product_id)
+	product, err := qtx.GetProductByIdForUpdate(ctx, product_id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return pgstore.Bid{}, errors.New("product not found")
+			return pgstore.Bid{}, ErrProductNotFound
 		}
 
 		return pgstore.Bid{}, err
 	}
 
-	highestBid, err := qtx.GetHighestBidByProductId(ctx, product_id)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if !product.PublishedAt.Valid {
+		return pgstore.Bid{}, ErrProductNotFound
+	}
+
+	if product.SellerID == bidder_id {
+		return pgstore.Bid{}, ErrSellerCannotBid
+	}
+
+	if err := requireVerifiedEmail(ctx, qtx, bidder_id); err != nil {
 		return pgstore.Bid{}, err
 	}
 
-	isFirstBid := errors.Is(err, pgx.ErrNoRows)
-	if isFirstBid {
-		if amount <= product.BasePrice {
-			slog.Info("BID REJECTED: Amount is less than or equal to base price.")
-			return pgstore.Bid{}, ErrBidTooLow
-		}
-	} else {
-		if amount <= highestBid.BidAmount {
-			slog.Info("BID REJECTED: Amount is less than or equal to highest bid.")
-			return pgstore.Bid{}, ErrBidTooLow
-		}
+	if time.Now().Before(product.StartsAt) {
+		return pgstore.Bid{}, ErrAuctionNotStarted
+	}
+
+	if !time.Now().Before(product.AuctionEnd) {
+		return pgstore.Bid{}, ErrAuctionClosed
+	}
+
+	if amount.Currency != product.Currency {
+		return pgstore.Bid{}, money.ErrCurrencyMismatch
+	}
+
+	if quantity < 1 || quantity > product.Quantity {
+		return pgstore.Bid{}, ErrInvalidBidQuantity
+	}
+
+	standing, err := qtx.ListStandingBids(ctx, product_id)
+	if err != nil {
+		return pgstore.Bid{}, err
+	}
+
+	if err := checkBidAmount(product, standing, bidder_id, amount.Amount); err != nil {
+		return pgstore.Bid{}, err
 	}
 
 	args := pgstore.CreateBidParams{
 		ProductID: product_id,
 		BidderID:  bidder_id,
-		BidAmount: amount,
+		BidAmount: amount.Amount,
+		DeviceID:  pgtype.Text{String: origin.DeviceID, Valid: origin.DeviceID != ""},
+		Quantity:  quantity,
+	}
+
+	if origin.IP.IsValid() {
+		args.IpAddress = &origin.IP
 	}
 
 	newBid, err := qtx.CreateBid(ctx, args)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			return pgstore.Bid{}, ErrBidTooLow
+		}
+
 		return pgstore.Bid{}, err
 	}
 
+	pseudonymArgs := pgstore.AssignBidderPseudonymParams{
+		ProductID: product_id,
+		UserID:    bidder_id,
+	}
+
+	if _, err := qtx.AssignBidderPseudonym(ctx, pseudonymArgs); err != nil {
+		return pgstore.Bid{}, err
+	}
+
+	if product.BidHoldPercent > 0 {
+		if err := holdFunds(ctx, qtx, newBid, product.Currency, int64(product.BidHoldPercent), outbidBidders(product, standing, newBid)); err != nil {
+			return pgstore.Bid{}, err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return pgstore.Bid{}, err
 	}
 
 	return newBid, nil
 }
+
+// checkBidAmount rejects amounts that could not win anything. The bidder's
+// own standing bid does not compete with the new one, which replaces it, but
+// a bid is never allowed to go below it.
+func checkBidAmount(product pgstore.Product, standing []pgstore.Bid, bidderId uuid.UUID, amount int64) error {
+	if amount <= product.BasePrice {
+		slog.Info("BID REJECTED: Amount is less than or equal to base price.")
+		return ErrBidTooLow
+	}
+
+	others := make([]pgstore.Bid, 0, len(standing))
+	for _, bid := range standing {
+		if bid.BidderID != bidderId {
+			others = append(others, bid)
+			continue
+		}
+
+		if amount < bid.BidAmount || (product.Quantity == 1 && amount == bid.BidAmount) {
+			slog.Info("BID REJECTED: Amount is less than the bidder's previous bid.")
+			return ErrBidTooLow
+		}
+	}
+
+	allocations, full := allocateUnits(others, product.Quantity, product.Pricing)
+	if full && amount <= allocations[len(allocations)-1].Bid.BidAmount {
+		slog.Info("BID REJECTED: Amount is less than or equal to the clearing price.")
+		return ErrBidTooLow
+	}
+
+	return nil
+}
+
+// outbidBidders lists who won units before the new bid and no longer does.
+func outbidBidders(product pgstore.Product, standing []pgstore.Bid, newBid pgstore.Bid) []uuid.UUID {
+	before, _ := allocateUnits(standing, product.Quantity, product.Pricing)
+
+	after := []pgstore.Bid{newBid}
+	for _, bid := range standing {
+		if bid.BidderID != newBid.BidderID {
+			after = append(after, bid)
+		}
+	}
+
+	winners := make(map[uuid.UUID]bool)
+	afterAllocations, _ := allocateUnits(after, product.Quantity, product.Pricing)
+	for _, allocation := range afterAllocations {
+		winners[allocation.Bid.BidderID] = true
+	}
+
+	var outbid []uuid.UUID
+	for _, allocation := range before {
+		if !winners[allocation.Bid.BidderID] {
+			outbid = append(outbid, allocation.Bid.BidderID)
+		}
+	}
+
+	return outbid
+}
+
+const (
+	PricingUniform  = "uniform"
+	PricingPayAsBid = "pay_as_bid"
+)
+
+// Allocation is the number of units a standing bid wins and the price it
+// pays for each of them.
+type Allocation struct {
+	Bid       pgstore.Bid
+	Quantity  int32
+	UnitPrice int64
+}
+
+// allocateUnits hands the units out to the standing bids, highest amount
+// first and earliest first on ties, until they run out; the last winner may
+// get fewer units than it asked for. With uniform pricing every winner pays
+// the lowest winning amount, with pay as bid each pays its own. full reports
+// whether every unit was taken.
+func allocateUnits(standing []pgstore.Bid, quantity int32, pricing string) (allocations []Allocation, full bool) {
+	ranked := slices.Clone(standing)
+	slices.SortFunc(ranked, func(a, b pgstore.Bid) int {
+		if c := cmp.Compare(b.BidAmount, a.BidAmount); c != 0 {
+			return c
+		}
+
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	remaining := quantity
+	for _, bid := range ranked {
+		if remaining == 0 {
+			break
+		}
+
+		units := min(bid.Quantity, remaining)
+		remaining -= units
+		allocations = append(allocations, Allocation{Bid: bid, Quantity: units, UnitPrice: bid.BidAmount})
+	}
+
+	if pricing == PricingUniform && len(allocations) > 0 {
+		clearing := allocations[len(allocations)-1].Bid.BidAmount
+		for i := range allocations {
+			allocations[i].UnitPrice = clearing
+		}
+	}
+
+	return allocations, remaining == 0
+}
+
+// AuctionBook summarizes who is winning a multi-unit auction right now.
+type AuctionBook struct {
+	ClearingPrice  money.Money
+	Quantity       int32
+	UnitsAllocated int32
+}
+
+// Book returns the current clearing price of the auction, the lowest amount
+// that still wins a unit, or the base price while nobody has bid.
+func (bs *BidsService) Book(ctx context.Context, productId uuid.UUID) (AuctionBook, error) {
+	product, err := bs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return AuctionBook{}, ErrProductNotFound
+		}
+
+		return AuctionBook{}, err
+	}
+
+	standing, err := bs.queries.ListStandingBids(ctx, productId)
+	if err != nil {
+		return AuctionBook{}, err
+	}
+
+	book := AuctionBook{ClearingPrice: clearingPrice(standing, product), Quantity: product.Quantity}
+
+	allocations, _ := allocateUnits(standing, product.Quantity, product.Pricing)
+	for _, allocation := range allocations {
+		book.UnitsAllocated += allocation.Quantity
+	}
+
+	return book, nil
+}
+
+// clearingPrice is the lowest standing amount that still wins a unit of the
+// product, or its base price while nobody has bid.
+func clearingPrice(standing []pgstore.Bid, product pgstore.Product) money.Money {
+	allocations, _ := allocateUnits(standing, product.Quantity, product.Pricing)
+	if len(allocations) == 0 {
+		return BasePrice(product)
+	}
+
+	return money.New(allocations[len(allocations)-1].Bid.BidAmount, product.Currency)
+}
+
+var ErrPseudonymNotFound = errors.New("no participant with this pseudonym in the auction")
+
+const pseudonymPrefix = "Bidder "
+
+// Pseudonym returns the stable name the user goes by in the auction,
+// assigning the next free number on their first participation. The product
+// row is locked like in PlaceBid so numbers are never handed out twice.
+func (bs *BidsService) Pseudonym(ctx context.Context, productId, userId uuid.UUID) (string, error) {
+	tx, err := bs.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := bs.queries.WithTx(tx)
+
+	if _, err := qtx.GetProductByIdForUpdate(ctx, productId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrProductNotFound
+		}
+
+		return "", err
+	}
+
+	args := pgstore.AssignBidderPseudonymParams{
+		ProductID: productId,
+		UserID:    userId,
+	}
+
+	pseudonym, err := qtx.AssignBidderPseudonym(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	return formatPseudonym(pseudonym.Number), nil
+}
+
+// ResolvePseudonym finds the user behind a pseudonym of the auction.
+func (bs *BidsService) ResolvePseudonym(ctx context.Context, productId uuid.UUID, pseudonym string) (uuid.UUID, error) {
+	raw, found := strings.CutPrefix(pseudonym, pseudonymPrefix)
+	if !found {
+		return uuid.UUID{}, ErrPseudonymNotFound
+	}
+
+	number, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return uuid.UUID{}, ErrPseudonymNotFound
+	}
+
+	args := pgstore.GetBidderPseudonymByNumberParams{
+		ProductID: productId,
+		Number:    int32(number),
+	}
+
+	row, err := bs.queries.GetBidderPseudonymByNumber(ctx, args)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrPseudonymNotFound
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	return row.UserID, nil
+}
+
+func formatPseudonym(number int32) string {
+	return pseudonymPrefix + strconv.Itoa(int(number))
+}
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+const (
+	AuctionStatusLive  = "live"
+	AuctionStatusEnded = "ended"
+)
+
+// BidHistoryFilter narrows a product's bid history. Zero times disable the
+// matching bound and an empty cursor starts from the most recent bid.
+type BidHistoryFilter struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Cursor        string
+	Limit         int32
+}
+
+// BidHistoryEntry is a bid as shown in the history. Bidder is the bidder's
+// pseudonym in the auction; BidderID and BidderName are only set when the
+// viewer is allowed to see who placed it.
+type BidHistoryEntry struct {
+	ID         uuid.UUID   `json:"id"`
+	Bidder     string      `json:"bidder"`
+	BidderID   *uuid.UUID  `json:"bidder_id,omitempty"`
+	BidderName string      `json:"bidder_name,omitempty"`
+	Amount     money.Money `json:"amount"`
+	Quantity   int32       `json:"quantity"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Voided     bool        `json:"voided"`
+}
+
+type BidHistoryPage struct {
+	Bids       []BidHistoryEntry `json:"bids"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// ListProductBids returns one page of a product's bids, newest first. Admins
+// see every bidder and the seller does too once the auction is over;
+// everybody else only sees who is behind their own bids.
+func (bs *BidsService) ListProductBids(ctx context.Context, productId, viewerId uuid.UUID, viewerIsAdmin bool, filter BidHistoryFilter) (BidHistoryPage, error) {
+	product, err := bs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BidHistoryPage{}, ErrProductNotFound
+		}
+
+		return BidHistoryPage{}, err
+	}
+
+	args := pgstore.ListBidsByProductIdParams{
+		ProductID: productId,
+		// One extra row tells whether there is a next page.
+		PageSize: filter.Limit + 1,
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		args.CreatedAfter = pgtype.Timestamptz{Time: filter.CreatedAfter, Valid: true}
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		args.CreatedBefore = pgtype.Timestamptz{Time: filter.CreatedBefore, Valid: true}
+	}
+
+	if filter.Cursor != "" {
+		createdAt, id, err := decodeBidCursor(filter.Cursor)
+		if err != nil {
+			return BidHistoryPage{}, ErrInvalidCursor
+		}
+
+		args.CursorCreatedAt = pgtype.Timestamptz{Time: createdAt, Valid: true}
+		args.CursorID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	rows, err := bs.queries.ListBidsByProductId(ctx, args)
+	if err != nil {
+		return BidHistoryPage{}, err
+	}
+
+	page := BidHistoryPage{Bids: make([]BidHistoryEntry, 0, len(rows))}
+
+	if len(rows) > int(filter.Limit) {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeBidCursor(last.CreatedAt, last.ID)
+	}
+
+	revealAll := viewerIsAdmin || (viewerId == product.SellerID && auctionStatus(product.AuctionEnd) == AuctionStatusEnded)
+
+	for _, row := range rows {
+		entry := BidHistoryEntry{
+			ID:        row.ID,
+			Bidder:    formatPseudonym(row.BidderNumber),
+			Amount:    money.New(row.BidAmount, product.Currency),
+			Quantity:  row.Quantity,
+			CreatedAt: row.CreatedAt,
+			Voided:    row.VoidedAt.Valid,
+		}
+
+		if revealAll || row.BidderID == viewerId {
+			bidderId := row.BidderID
+			entry.BidderID = &bidderId
+			entry.BidderName = row.BidderName
+		}
+
+		page.Bids = append(page.Bids, entry)
+	}
+
+	return page, nil
+}
+
+// UserBidSummary describes an auction the user bid on from their point of
+// view.
+type UserBidSummary struct {
+	ProductID    uuid.UUID   `json:"product_id"`
+	ProductName  string      `json:"product_name"`
+	MyHighestBid money.Money `json:"my_highest_bid"`
+	CurrentPrice money.Money `json:"current_price"`
+	IsWinning    bool        `json:"is_winning"`
+	Status       string      `json:"status"`
+	AuctionEnd   time.Time   `json:"auction_end"`
+	LastBidAt    time.Time   `json:"last_bid_at"`
+}
+
+type UserBidPage struct {
+	Auctions []UserBidSummary `json:"auctions"`
+	Page     int32            `json:"page"`
+	Limit    int32            `json:"limit"`
+	Total    int64            `json:"total"`
+}
+
+// ListUserBids lists a page of the auctions the user has a valid bid on,
+// most recently bid first. The current price is the clearing price of the
+// auction; the user is winning while the allocation gives them a unit, and
+// once the auction is finalized only if they are among its results.
+func (bs *BidsService) ListUserBids(ctx context.Context, userId uuid.UUID, page, limit int32) (UserBidPage, error) {
+	rows, err := bs.queries.ListBidSummariesByBidderId(ctx, pgstore.ListBidSummariesByBidderIdParams{
+		BidderID: userId,
+		Limit:    limit,
+		Offset:   (page - 1) * limit,
+	})
+	if err != nil {
+		return UserBidPage{}, err
+	}
+
+	total, err := bs.queries.CountBidAuctionsByBidderId(ctx, userId)
+	if err != nil {
+		return UserBidPage{}, err
+	}
+
+	productIds := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		productIds = append(productIds, row.ProductID)
+	}
+
+	standingBids, err := bs.queries.ListStandingBidsByProductIds(ctx, productIds)
+	if err != nil {
+		return UserBidPage{}, err
+	}
+
+	standing := make(map[uuid.UUID][]pgstore.Bid, len(rows))
+	for _, bid := range standingBids {
+		standing[bid.ProductID] = append(standing[bid.ProductID], bid)
+	}
+
+	summaries := make([]UserBidSummary, 0, len(rows))
+	for _, row := range rows {
+		currentPrice := row.BasePrice
+		isWinning := row.Won
+
+		allocations, _ := allocateUnits(standing[row.ProductID], row.Quantity, row.Pricing)
+		if len(allocations) > 0 {
+			currentPrice = allocations[len(allocations)-1].Bid.BidAmount
+		}
+
+		if !row.FinalizedAt.Valid {
+			isWinning = slices.ContainsFunc(allocations, func(allocation Allocation) bool {
+				return allocation.Bid.BidderID == userId
+			})
+		}
+
+		summaries = append(summaries, UserBidSummary{
+			ProductID:    row.ProductID,
+			ProductName:  row.ProductName,
+			MyHighestBid: money.New(row.MyHighestBid, row.Currency),
+			CurrentPrice: money.New(currentPrice, row.Currency),
+			IsWinning:    isWinning,
+			Status:       productStatus(row.StartsAt, row.AuctionEnd),
+			AuctionEnd:   row.AuctionEnd,
+			LastBidAt:    row.LastBidAt,
+		})
+	}
+
+	return UserBidPage{Auctions: summaries, Page: page, Limit: limit, Total: total}, nil
+}
+
+func auctionStatus(auctionEnd time.Time) string {
+	if time.Now().Before(auctionEnd) {
+		return AuctionStatusLive
+	}
+
+	return AuctionStatusEnded
+}
+
+func encodeBidCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeBidCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, err
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return time.Time{}, uuid.UUID{}, ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, err
+	}
+
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, err
+	}
+
+	return t, parsedId, nil
+}
+
+var ErrAuctionNotEnded = errors.New("the auction for this product has not ended yet")
+
+// FinalizeAuction settles an auction that is over: it records one result
+// per winner, marks the product as sold when there is any and releases the
+// holds of everyone else. Units whose price ends below the reserve are not
+// sold, and an unsold product with relists left in its policy is relisted
+// at a lower price. Finalizing an auction twice does nothing.
+func (bs *BidsService) FinalizeAuction(ctx context.Context, productId uuid.UUID) error {
+	tx, err := bs.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := bs.queries.WithTx(tx)
+
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFound
+		}
+
+		return err
+	}
+
+	if product.FinalizedAt.Valid {
+		return nil
+	}
+
+	if time.Now().Before(product.AuctionEnd) {
+		return ErrAuctionNotEnded
+	}
+
+	standing, err := qtx.ListStandingBids(ctx, productId)
+	if err != nil {
+		return err
+	}
+
+	allocations, _ := allocateUnits(standing, product.Quantity, product.Pricing)
+	if product.ReservePrice.Valid {
+		allocations = slices.DeleteFunc(allocations, func(allocation Allocation) bool {
+			return allocation.UnitPrice < product.ReservePrice.Int64
+		})
+	}
+
+	for _, allocation := range allocations {
+		total, err := money.New(allocation.UnitPrice, product.Currency).Mul(int64(allocation.Quantity))
+		if err != nil {
+			return err
+		}
+
+		if _, err := qtx.CreateAuctionResult(ctx, pgstore.CreateAuctionResultParams{
+			ProductID: productId,
+			BidderID:  allocation.Bid.BidderID,
+			BidID:     allocation.Bid.ID,
+			Quantity:  allocation.Quantity,
+			UnitPrice: allocation.UnitPrice,
+			Total:     total.Amount,
+			Currency:  product.Currency,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := qtx.FinalizeProduct(ctx, pgstore.FinalizeProductParams{ID: productId, IsSold: len(allocations) > 0}); err != nil {
+		return err
+	}
+
+	holds, err := qtx.ListReleasableBidHolds(ctx, pgtype.UUID{Bytes: productId, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	for _, hold := range holds {
+		if err := releaseHold(ctx, qtx, hold); err != nil {
+			return err
+		}
+	}
+
+	if len(allocations) == 0 && product.AutoRelistRemaining > 0 {
+		auctionEnd := time.Now().Add(product.AuctionEnd.Sub(product.StartsAt))
+		if _, err := relistProduct(ctx, qtx, product, autoRelistPrice(product), auctionEnd, product.AutoRelistRemaining-1); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// autoRelistPrice is the base price of the automatic relist of product: its
+// base price lowered by the drop of its policy, but never below one minor
+// unit.
+func autoRelistPrice(product pgstore.Product) int64 {
+	return max(product.BasePrice*int64(100-product.AutoRelistDropPercent)/100, 1)
+}
+
+const finalizationBatchSize = 100
+
+// FinalizeEndedAuctions finalizes every auction that ended without being
+// finalized, e.g. because its room was not running at the time.
+func (bs *BidsService) FinalizeEndedAuctions(ctx context.Context) error {
+	for {
+		ids, err := bs.queries.ListUnfinalizedEndedProductIds(ctx, finalizationBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := bs.FinalizeAuction(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		if len(ids) < finalizationBatchSize {
+			return nil
+		}
+	}
+}
+
+// RunFinalization finalizes ended auctions every interval until ctx is
+// cancelled. It catches auctions whose room was not running when they
+// ended, e.g. during a restart.
+func (bs *BidsService) RunFinalization(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := bs.FinalizeEndedAuctions(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("failed to finalize ended auctions", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// AuctionResultEntry is a winner of a finalized auction. Like in the bid
+// history, BidderID and BidderName are only set when the viewer may see who
+// won.
+type AuctionResultEntry struct {
+	Bidder     string      `json:"bidder"`
+	BidderID   *uuid.UUID  `json:"bidder_id,omitempty"`
+	BidderName string      `json:"bidder_name,omitempty"`
+	Quantity   int32       `json:"quantity"`
+	UnitPrice  money.Money `json:"unit_price"`
+	Total      money.Money `json:"total"`
+}
+
+type AuctionResults struct {
+	Finalized bool                 `json:"finalized"`
+	Pricing   string               `json:"pricing"`
+	Results   []AuctionResultEntry `json:"results"`
+}
+
+// ListAuctionResults returns the winners of an auction. The seller and
+// admins see who they are, everybody else only recognizes themselves.
+func (bs *BidsService) ListAuctionResults(ctx context.Context, productId, viewerId uuid.UUID, viewerIsAdmin bool) (AuctionResults, error) {
+	product, err := bs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return AuctionResults{}, ErrProductNotFound
+		}
+
+		return AuctionResults{}, err
+	}
+
+	rows, err := bs.queries.ListAuctionResultsByProductId(ctx, productId)
+	if err != nil {
+		return AuctionResults{}, err
+	}
+
+	results := AuctionResults{
+		Finalized: product.FinalizedAt.Valid,
+		Pricing:   product.Pricing,
+		Results:   make([]AuctionResultEntry, 0, len(rows)),
+	}
+
+	revealAll := viewerIsAdmin || viewerId == product.SellerID
+
+	for _, row := range rows {
+		entry := AuctionResultEntry{
+			Bidder:    formatPseudonym(row.BidderNumber),
+			Quantity:  row.Quantity,
+			UnitPrice: money.New(row.UnitPrice, row.Currency),
+			Total:     money.New(row.Total, row.Currency),
+		}
+
+		if revealAll || row.BidderID == viewerId {
+			bidderId := row.BidderID
+			entry.BidderID = &bidderId
+			entry.BidderName = row.BidderName
+		}
+
+		results.Results = append(results.Results, entry)
+	}
+
+	return results, nil
+}
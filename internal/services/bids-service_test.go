@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/store/blobstore"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testPool connects to the migrated database given as a connection string in
+// GOBID_TEST_DATABASE_URL, and skips the test when there is none.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("GOBID_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("GOBID_TEST_DATABASE_URL is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// TestPlaceBidConcurrently has many bidders race for the same amounts and
+// checks that the accepted bids are strictly increasing in the order they
+// were accepted.
+func TestPlaceBidConcurrently(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+
+	const (
+		bidders = 20
+		rounds  = 50
+	)
+
+	pool := testPool(t)
+	ctx := context.Background()
+
+	userService := NewUserService(pool)
+	// The test never uploads images, so any directory will do.
+	blobs, err := blobstore.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	productService := NewProductService(pool, blobs)
+	bidsService := NewBidsService(pool)
+
+	run := uuid.NewString()[:8]
+
+	var userIds []uuid.UUID
+	var productId uuid.UUID
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+
+		if productId != uuid.Nil {
+			if _, err := pool.Exec(ctx, "DELETE FROM bids WHERE product_id = $1", productId); err != nil {
+				t.Errorf("cleanup: %v", err)
+			}
+
+			if _, err := pool.Exec(ctx, "DELETE FROM products WHERE id = $1", productId); err != nil {
+				t.Errorf("cleanup: %v", err)
+			}
+		}
+
+		if _, err := pool.Exec(ctx, "DELETE FROM users WHERE id = ANY($1)", userIds); err != nil {
+			t.Errorf("cleanup: %v", err)
+		}
+	})
+
+	newUser := func(name string) uuid.UUID {
+		id, err := userService.CreateUser(ctx, fmt.Sprintf("%s-%s", name, run), fmt.Sprintf("%s-%s@bidstress.local", name, run), "bidstress-password", "created by the bid stress test")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		userIds = append(userIds, id)
+
+		// There is no inbox to read the verification email from.
+		if err := userService.MarkEmailVerified(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		return id
+	}
+
+	sellerId := newUser("seller")
+	product, err := productService.CreateProduct(ctx, ProductListing{
+		SellerID:    sellerId,
+		Name:        "Bid stress " + run,
+		Description: "product used by the bid stress test",
+		BasePrice:   money.New(100, "BRL"),
+		AuctionEnd:  time.Now().Add(3 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	productId = product.ID
+
+	if product, err = productService.PublishProduct(ctx, product.ID, sellerId); err != nil {
+		t.Fatal(err)
+	}
+
+	bidderIds := make([]uuid.UUID, bidders)
+	for i := range bidderIds {
+		bidderIds[i] = newUser(fmt.Sprintf("bidder%d", i))
+	}
+
+	var accepted atomic.Int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, bidders)
+
+	for _, bidderId := range bidderIds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			for round := 1; round <= rounds; round++ {
+				amount := money.New(product.BasePrice+int64(round), product.Currency)
+
+				_, err := bidsService.PlaceBid(ctx, product.ID, bidderId, amount, 1, RequestOrigin{})
+				switch {
+				case err == nil:
+					accepted.Add(1)
+				case errors.Is(err, ErrBidTooLow):
+				default:
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("PlaceBid: %v", err)
+	}
+
+	bids, err := pgstore.New(pool).GetBidsByProductId(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if int64(len(bids)) != accepted.Load() {
+		t.Errorf("%d bids accepted, but %d rows stored", accepted.Load(), len(bids))
+	}
+
+	slices.SortFunc(bids, func(a, b pgstore.Bid) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	for i := 1; i < len(bids); i++ {
+		if bids[i].BidAmount <= bids[i-1].BidAmount {
+			t.Errorf("bid %s (%d) accepted after bid %s (%d)", bids[i].ID, bids[i].BidAmount, bids[i-1].ID, bids[i-1].BidAmount)
+		}
+	}
+}
+
+func TestClearingPrice(t *testing.T) {
+	start := time.Now()
+	bid := func(amount int64, quantity int32, after time.Duration) pgstore.Bid {
+		return pgstore.Bid{ID: uuid.New(), BidderID: uuid.New(), BidAmount: amount, Quantity: quantity, CreatedAt: start.Add(after)}
+	}
+
+	standing := []pgstore.Bid{bid(900, 1, 0), bid(1500, 2, time.Second), bid(1200, 1, 2*time.Second), bid(1100, 3, 3*time.Second)}
+
+	tests := []struct {
+		quantity int32
+		standing []pgstore.Bid
+		want     int64
+	}{
+		{1, standing, 1500},
+		{3, standing, 1200},
+		{4, standing, 1100},
+		{10, standing, 900},
+		{3, nil, 500},
+	}
+
+	for _, tt := range tests {
+		product := pgstore.Product{BasePrice: 500, Currency: "BRL", Quantity: tt.quantity, Pricing: PricingUniform}
+		if got := clearingPrice(tt.standing, product); got != money.New(tt.want, "BRL") {
+			t.Errorf("clearing price for %d units = %v, want %d", tt.quantity, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrCategoryNameTaken = errors.New("a category with this name already exists at this level")
+	ErrCategoryCycle     = errors.New("a category cannot be moved under itself or one of its subcategories")
+)
+
+type CategoryService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewCategoryService(pool *pgxpool.Pool) CategoryService {
+	return CategoryService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// CategoryNode is a category in the browse tree. LiveCount counts the live
+// auctions of the category and of all its subcategories.
+type CategoryNode struct {
+	ID        uuid.UUID       `json:"id"`
+	ParentID  *uuid.UUID      `json:"parent_id"`
+	Name      string          `json:"name"`
+	LiveCount int64           `json:"live_count"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Children  []*CategoryNode `json:"children"`
+}
+
+// CategoryTree returns the top level categories with their subcategories
+// nested under them, siblings sorted by name.
+func (cs *CategoryService) CategoryTree(ctx context.Context) ([]*CategoryNode, error) {
+	rows, err := cs.queries.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uuid.UUID]*CategoryNode, len(rows))
+	for _, row := range rows {
+		nodes[row.ID] = &CategoryNode{
+			ID:        row.ID,
+			ParentID:  nullableUUID(row.ParentID),
+			Name:      row.Name,
+			LiveCount: row.LiveCount,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			Children:  []*CategoryNode{},
+		}
+	}
+
+	roots := []*CategoryNode{}
+	for _, row := range rows {
+		node := nodes[row.ID]
+		if parent, ok := nodes[uuid.UUID(row.ParentID.Bytes)]; row.ParentID.Valid && ok {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+
+		roots = append(roots, node)
+	}
+
+	return roots, nil
+}
+
+func (cs *CategoryService) GetCategoryById(ctx context.Context, categoryId uuid.UUID) (pgstore.Category, error) {
+	category, err := cs.queries.GetCategoryById(ctx, categoryId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Category{}, ErrCategoryNotFound
+		}
+
+		return pgstore.Category{}, err
+	}
+
+	return category, nil
+}
+
+// CreateCategory adds a category under parentId, or at the top level when
+// parentId is uuid.Nil.
+func (cs *CategoryService) CreateCategory(ctx context.Context, parentId uuid.UUID, name string) (pgstore.Category, error) {
+	if parentId != uuid.Nil {
+		if _, err := cs.GetCategoryById(ctx, parentId); err != nil {
+			return pgstore.Category{}, err
+		}
+	}
+
+	category, err := cs.queries.CreateCategory(ctx, pgstore.CreateCategoryParams{
+		ParentID: optionalUUID(parentId),
+		Name:     strings.TrimSpace(name),
+	})
+	if err != nil {
+		return pgstore.Category{}, categoryWriteError(err)
+	}
+
+	return category, nil
+}
+
+// UpdateCategory renames a category and moves it under parentId, or to the
+// top level when parentId is uuid.Nil. Its products and subcategories move
+// along with it.
+func (cs *CategoryService) UpdateCategory(ctx context.Context, categoryId, parentId uuid.UUID, name string) (pgstore.Category, error) {
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Category{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := cs.queries.WithTx(tx)
+
+	if parentId != uuid.Nil {
+		if _, err := qtx.GetCategoryById(ctx, parentId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.Category{}, ErrCategoryNotFound
+			}
+
+			return pgstore.Category{}, err
+		}
+
+		subtree, err := qtx.ListCategorySubtreeIds(ctx, categoryId)
+		if err != nil {
+			return pgstore.Category{}, err
+		}
+
+		if slices.Contains(subtree, parentId) {
+			return pgstore.Category{}, ErrCategoryCycle
+		}
+	}
+
+	category, err := qtx.UpdateCategory(ctx, pgstore.UpdateCategoryParams{
+		ID:       categoryId,
+		ParentID: optionalUUID(parentId),
+		Name:     strings.TrimSpace(name),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Category{}, ErrCategoryNotFound
+		}
+
+		return pgstore.Category{}, categoryWriteError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Category{}, err
+	}
+
+	return category, nil
+}
+
+// DeleteCategory removes a category without taking anything out of the
+// catalog: its products and subcategories are handed to its parent, and
+// products of a top level category are left uncategorized.
+func (cs *CategoryService) DeleteCategory(ctx context.Context, categoryId uuid.UUID) error {
+	tx, err := cs.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := cs.queries.WithTx(tx)
+
+	category, err := qtx.GetCategoryById(ctx, categoryId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCategoryNotFound
+		}
+
+		return err
+	}
+
+	if err := qtx.ReassignCategoryProducts(ctx, pgstore.ReassignCategoryProductsParams{
+		NewCategoryID: category.ParentID,
+		CategoryID:    category.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.ReparentCategoryChildren(ctx, pgstore.ReparentCategoryChildrenParams{
+		NewParentID: category.ParentID,
+		ID:          category.ID,
+	}); err != nil {
+		return categoryWriteError(err)
+	}
+
+	if err := qtx.DeleteCategory(ctx, category.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func categoryWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrCategoryNameTaken
+	}
+
+	return err
+}
+
+func optionalUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: id != uuid.Nil}
+}
+
+func nullableUUID(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+
+	value := uuid.UUID(id.Bytes)
+	return &value
+}
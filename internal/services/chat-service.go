@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrChatMessageNotFound = errors.New("chat message not found")
+
+const chatHistorySize = 50
+
+type ChatService struct {
+	pool         *pgxpool.Pool
+	queries      *pgstore.Queries
+	blockedWords []string
+}
+
+func NewChatService(pool *pgxpool.Pool, blockedWords []string) ChatService {
+	return ChatService{
+		pool:         pool,
+		queries:      pgstore.New(pool),
+		blockedWords: blockedWords,
+	}
+}
+
+func (cs *ChatService) BlockedWords() []string {
+	return cs.blockedWords
+}
+
+func (cs *ChatService) SendMessage(ctx context.Context, productId, senderId uuid.UUID, content string) (pgstore.RoomMessage, error) {
+	args := pgstore.CreateRoomMessageParams{
+		ProductID: productId,
+		SenderID:  senderId,
+		Content:   content,
+	}
+
+	return cs.queries.CreateRoomMessage(ctx, args)
+}
+
+// GetHistory returns the latest messages of a room, oldest first, so they can
+// be replayed to a client that has just joined.
+func (cs *ChatService) GetHistory(ctx context.Context, productId uuid.UUID) ([]pgstore.RoomMessage, error) {
+	args := pgstore.GetRecentRoomMessagesByProductIdParams{
+		ProductID: productId,
+		Limit:     chatHistorySize,
+	}
+
+	messages, err := cs.queries.GetRecentRoomMessagesByProductId(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+func (cs *ChatService) DeleteMessage(ctx context.Context, productId, messageId, deletedBy uuid.UUID) error {
+	args := pgstore.DeleteRoomMessageParams{
+		ID:        messageId,
+		ProductID: productId,
+		DeletedBy: deletedBy,
+	}
+
+	if _, err := cs.queries.DeleteRoomMessage(ctx, args); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrChatMessageNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
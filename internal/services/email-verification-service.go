@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/mailer"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrEmailNotVerified         = errors.New("confirm your email address before bidding or selling")
+	ErrEmailAlreadyVerified     = errors.New("email address is already verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+)
+
+// ErrCodeEmailNotVerified is sent to clients together with
+// ErrEmailNotVerified so they can offer to resend the confirmation email.
+const ErrCodeEmailNotVerified = "email_not_verified"
+
+const emailVerificationTTL = 24 * time.Hour
+
+// EmailVerificationService confirms that users own the email address they
+// signed up with. Tokens are signed with secret and name a row of
+// email_verifications, which is marked used when the token is redeemed.
+type EmailVerificationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	mailer  mailer.Mailer
+	secret  []byte
+}
+
+func NewEmailVerificationService(pool *pgxpool.Pool, m mailer.Mailer, secret []byte) EmailVerificationService {
+	return EmailVerificationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		mailer:  m,
+		secret:  secret,
+	}
+}
+
+// SendVerification emails userId a new verification token. Tokens sent
+// before stay valid until they expire.
+func (evs *EmailVerificationService) SendVerification(ctx context.Context, userId uuid.UUID) error {
+	user, err := evs.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+
+		return err
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return ErrEmailAlreadyVerified
+	}
+
+	if err := evs.queries.DeleteStaleEmailVerifications(ctx, userId); err != nil {
+		return err
+	}
+
+	args := pgstore.CreateEmailVerificationParams{
+		UserID:    userId,
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}
+
+	verification, err := evs.queries.CreateEmailVerification(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	token := evs.sign(verification.ID, verification.ExpiresAt)
+
+	return evs.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address to start bidding and selling on gobid by sending this token to POST /api/v1/users/email/verify:\n\n%s\n\nThe token expires in %s.\n",
+			user.UserName, token, emailVerificationTTL),
+	})
+}
+
+// VerifyEmail redeems token and marks the email of its user as verified,
+// returning the user id. A token works once, and only until it expires.
+func (evs *EmailVerificationService) VerifyEmail(ctx context.Context, token string) (uuid.UUID, error) {
+	id, ok := evs.verify(token)
+	if !ok {
+		return uuid.UUID{}, ErrInvalidVerificationToken
+	}
+
+	tx, err := evs.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := evs.queries.WithTx(tx)
+
+	userId, err := qtx.UseEmailVerification(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrInvalidVerificationToken
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	if err := qtx.MarkUserEmailVerified(ctx, userId); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return userId, nil
+}
+
+// sign encodes the verification id and its expiry followed by their
+// HMAC-SHA256, both in unpadded base64url.
+func (evs *EmailVerificationService) sign(id uuid.UUID, expiresAt time.Time) string {
+	payload := binary.BigEndian.AppendUint64(id[:], uint64(expiresAt.Unix()))
+
+	mac := hmac.New(sha256.New, evs.secret)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature and expiry of token and returns the
+// verification id it carries.
+func (evs *EmailVerificationService) verify(token string) (uuid.UUID, bool) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.UUID{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != len(uuid.UUID{})+8 {
+		return uuid.UUID{}, false
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return uuid.UUID{}, false
+	}
+
+	mac := hmac.New(sha256.New, evs.secret)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return uuid.UUID{}, false
+	}
+
+	id := uuid.UUID(payload[:16])
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	if !time.Now().Before(expiresAt) {
+		return uuid.UUID{}, false
+	}
+
+	return id, true
+}
+
+// requireVerifiedEmail returns ErrEmailNotVerified unless userId has
+// confirmed their email address.
+func requireVerifiedEmail(ctx context.Context, q *pgstore.Queries, userId uuid.UUID) error {
+	user, err := q.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if !user.EmailVerifiedAt.Valid {
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
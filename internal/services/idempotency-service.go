@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+	ErrIdempotencyKeyMismatch   = errors.New("this idempotency key was already used for a different request")
+)
+
+// A completed key is kept for IdempotencyKeyTTL. A claim whose request is
+// still running only holds the key for IdempotencyLease, so a key claimed
+// by a request that never completed, e.g. because the server crashed, can
+// be used again once the lease runs out. The lease must outlast the slowest
+// idempotent request, a large import.
+const (
+	IdempotencyKeyTTL = 24 * time.Hour
+	IdempotencyLease  = 5 * time.Minute
+)
+
+type IdempotencyService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewIdempotencyService(pool *pgxpool.Pool) IdempotencyService {
+	return IdempotencyService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// IdempotentResult is the outcome stored for an idempotency key, replayed
+// verbatim when the same key is used again.
+type IdempotentResult struct {
+	StatusCode int
+	Body       json.RawMessage
+}
+
+// HashIdempotentRequest identifies a request by its parts, so a key can only
+// be replayed for the same request.
+func HashIdempotentRequest(parts ...string) []byte {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hash.Sum(nil)
+}
+
+// Begin claims the key for a new request identified by requestHash. When the
+// key was already used for the same request and that request finished, the
+// stored result is returned with replay set to true and the caller must not
+// do the work again. A key used for a different request is refused with
+// ErrIdempotencyKeyMismatch.
+func (is *IdempotencyService) Begin(ctx context.Context, userId uuid.UUID, scope, key string, requestHash []byte) (result IdempotentResult, replay bool, err error) {
+	args := pgstore.ClaimIdempotencyKeyParams{
+		UserID:      userId,
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(IdempotencyLease),
+	}
+
+	_, err = is.queries.ClaimIdempotencyKey(ctx, args)
+	if err == nil {
+		return IdempotentResult{}, false, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return IdempotentResult{}, false, err
+	}
+
+	stored, err := is.queries.GetIdempotencyKey(ctx, pgstore.GetIdempotencyKeyParams{UserID: userId, Scope: scope, Key: key})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return IdempotentResult{}, false, ErrIdempotencyKeyInProgress
+		}
+
+		return IdempotentResult{}, false, err
+	}
+
+	if !bytes.Equal(stored.RequestHash, requestHash) {
+		return IdempotentResult{}, false, ErrIdempotencyKeyMismatch
+	}
+
+	if stored.StatusCode == 0 {
+		return IdempotentResult{}, false, ErrIdempotencyKeyInProgress
+	}
+
+	return IdempotentResult{StatusCode: int(stored.StatusCode), Body: stored.Response}, true, nil
+}
+
+func (is *IdempotencyService) Complete(ctx context.Context, userId uuid.UUID, scope, key string, statusCode int, body any) error {
+	response, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	args := pgstore.CompleteIdempotencyKeyParams{
+		UserID:     userId,
+		Scope:      scope,
+		Key:        key,
+		StatusCode: int32(statusCode),
+		Response:   response,
+		ExpiresAt:  time.Now().Add(IdempotencyKeyTTL),
+	}
+
+	return is.queries.CompleteIdempotencyKey(ctx, args)
+}
+
+// Release forgets a claimed key whose request failed unexpectedly, so the
+// client can retry it.
+func (is *IdempotencyService) Release(ctx context.Context, userId uuid.UUID, scope, key string) error {
+	return is.queries.DeleteIdempotencyKey(ctx, pgstore.DeleteIdempotencyKeyParams{UserID: userId, Scope: scope, Key: key})
+}
+
+func (is *IdempotencyService) PurgeExpired(ctx context.Context) error {
+	return is.queries.DeleteExpiredIdempotencyKeys(ctx)
+}
+
+// RunCleanup deletes expired keys every interval until ctx is cancelled.
+func (is *IdempotencyService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := is.PurgeExpired(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("failed to purge expired idempotency keys", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/blobstore"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	MaxImageBytes       = 10 << 20
+	MaxImageDimension   = 4096
+	MinImageDimension   = 100
+	MaxImagesPerProduct = 12
+	thumbnailSize       = 320
+	thumbnailQuality    = 80
+	// maxImageDecodes caps how many uploads are decoded at once. A decoded
+	// image of the largest allowed size takes 64 MiB.
+	maxImageDecodes = 2
+)
+
+var (
+	ErrImageNotFound     = errors.New("image not found")
+	ErrUnsupportedImage  = errors.New("the file must be a JPEG, PNG or GIF image")
+	ErrImageTooLarge     = errors.New("the image must be at most 10 MiB")
+	ErrImageDimensions   = errors.New("the image must be between 100 and 4096 pixels wide and tall")
+	ErrTooManyImages     = errors.New("the product already has the maximum number of images")
+	ErrInvalidImageOrder = errors.New("the new order must list every image of the product exactly once")
+)
+
+// imageDecodes holds a slot for every upload being decoded.
+var imageDecodes = make(chan struct{}, maxImageDecodes)
+
+// allowedImageTypes are the sniffed content types accepted for upload.
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+type ImageService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	blobs   blobstore.BlobStore
+}
+
+func NewImageService(pool *pgxpool.Pool, blobs blobstore.BlobStore) ImageService {
+	return ImageService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		blobs:   blobs,
+	}
+}
+
+// ProductImage is an image as listed in product responses.
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	Position     int32     `json:"position"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	SizeBytes    int64     `json:"size_bytes"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+}
+
+func newProductImage(image pgstore.ProductImage) ProductImage {
+	url := fmt.Sprintf("/api/v1/products/%s/images/%s", image.ProductID, image.ID)
+
+	return ProductImage{
+		ID:           image.ID,
+		Position:     image.Position,
+		ContentType:  image.ContentType,
+		Width:        image.Width,
+		Height:       image.Height,
+		SizeBytes:    image.SizeBytes,
+		URL:          url,
+		ThumbnailURL: url + "/thumbnail",
+	}
+}
+
+// AddProductImage validates an uploaded image, stores it with a JPEG
+// thumbnail and appends it to the end of the product's image list. The
+// content type is sniffed from the data; whatever the client declared is
+// ignored.
+func (is *ImageService) AddProductImage(ctx context.Context, productId, sellerId uuid.UUID, data []byte) (ProductImage, error) {
+	if len(data) > MaxImageBytes {
+		return ProductImage{}, ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if !allowedImageTypes[contentType] {
+		return ProductImage{}, ErrUnsupportedImage
+	}
+
+	// The header is checked before decoding so a small file claiming huge
+	// dimensions never gets a full size buffer allocated.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProductImage{}, ErrUnsupportedImage
+	}
+
+	if config.Width < MinImageDimension || config.Height < MinImageDimension ||
+		config.Width > MaxImageDimension || config.Height > MaxImageDimension {
+		return ProductImage{}, ErrImageDimensions
+	}
+
+	thumbnail, err := makeThumbnail(ctx, data)
+	if err != nil {
+		return ProductImage{}, err
+	}
+
+	tx, err := is.pool.Begin(ctx)
+	if err != nil {
+		return ProductImage{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := is.queries.WithTx(tx)
+
+	if _, err := lockSellerProduct(ctx, qtx, productId, sellerId); err != nil {
+		return ProductImage{}, err
+	}
+
+	existing, err := qtx.ListProductImages(ctx, []uuid.UUID{productId})
+	if err != nil {
+		return ProductImage{}, err
+	}
+
+	if len(existing) >= MaxImagesPerProduct {
+		return ProductImage{}, ErrTooManyImages
+	}
+
+	imageId := uuid.New()
+	blobKey := fmt.Sprintf("products/%s/%s", productId, imageId)
+
+	row, err := qtx.CreateProductImage(ctx, pgstore.CreateProductImageParams{
+		ID:           imageId,
+		ProductID:    productId,
+		ContentType:  contentType,
+		Width:        int32(config.Width),
+		Height:       int32(config.Height),
+		SizeBytes:    int64(len(data)),
+		BlobKey:      blobKey,
+		ThumbnailKey: blobKey + "-thumbnail.jpg",
+	})
+	if err != nil {
+		return ProductImage{}, err
+	}
+
+	if err := is.blobs.Put(ctx, row.BlobKey, bytes.NewReader(data)); err != nil {
+		return ProductImage{}, err
+	}
+
+	if err := is.blobs.Put(ctx, row.ThumbnailKey, bytes.NewReader(thumbnail)); err != nil {
+		deleteImageBlobs(ctx, is.queries, is.blobs, []pgstore.ProductImage{row})
+		return ProductImage{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		deleteImageBlobs(ctx, is.queries, is.blobs, []pgstore.ProductImage{row})
+		return ProductImage{}, err
+	}
+
+	return newProductImage(row), nil
+}
+
+// DeleteProductImage removes one image of the product and its files.
+func (is *ImageService) DeleteProductImage(ctx context.Context, productId, imageId, sellerId uuid.UUID) error {
+	tx, err := is.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := is.queries.WithTx(tx)
+
+	if _, err := lockSellerProduct(ctx, qtx, productId, sellerId); err != nil {
+		return err
+	}
+
+	image, err := qtx.GetProductImage(ctx, pgstore.GetProductImageParams{ID: imageId, ProductID: productId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrImageNotFound
+		}
+
+		return err
+	}
+
+	if err := qtx.DeleteProductImage(ctx, image.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	deleteImageBlobs(ctx, is.queries, is.blobs, []pgstore.ProductImage{image})
+	return nil
+}
+
+// ReorderProductImages sets the order of the product images to the given
+// list, which must contain each of them exactly once.
+func (is *ImageService) ReorderProductImages(ctx context.Context, productId, sellerId uuid.UUID, imageIds []uuid.UUID) ([]ProductImage, error) {
+	tx, err := is.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := is.queries.WithTx(tx)
+
+	if _, err := lockSellerProduct(ctx, qtx, productId, sellerId); err != nil {
+		return nil, err
+	}
+
+	existing, err := qtx.ListProductImages(ctx, []uuid.UUID{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imageIds) != len(existing) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	byId := make(map[uuid.UUID]pgstore.ProductImage, len(existing))
+	for _, image := range existing {
+		byId[image.ID] = image
+	}
+
+	images := make([]ProductImage, 0, len(imageIds))
+	for position, imageId := range imageIds {
+		image, ok := byId[imageId]
+		if !ok {
+			return nil, ErrInvalidImageOrder
+		}
+
+		delete(byId, imageId)
+
+		if err := qtx.SetProductImagePosition(ctx, pgstore.SetProductImagePositionParams{ID: imageId, Position: int32(position)}); err != nil {
+			return nil, err
+		}
+
+		image.Position = int32(position)
+		images = append(images, newProductImage(image))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// OpenProductImage returns the stored image, or its thumbnail, along with
+// its content type. Images of drafts are only served to their seller.
+func (is *ImageService) OpenProductImage(ctx context.Context, productId, imageId, viewerId uuid.UUID, thumbnail bool) (io.ReadCloser, string, error) {
+	product, err := is.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrImageNotFound
+		}
+
+		return nil, "", err
+	}
+
+	if !product.PublishedAt.Valid && product.SellerID != viewerId {
+		return nil, "", ErrImageNotFound
+	}
+
+	image, err := is.queries.GetProductImage(ctx, pgstore.GetProductImageParams{ID: imageId, ProductID: productId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrImageNotFound
+		}
+
+		return nil, "", err
+	}
+
+	key, contentType := image.BlobKey, image.ContentType
+	if thumbnail {
+		key, contentType = image.ThumbnailKey, "image/jpeg"
+	}
+
+	content, err := is.blobs.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrBlobNotFound) {
+			return nil, "", ErrImageNotFound
+		}
+
+		return nil, "", err
+	}
+
+	return content, contentType, nil
+}
+
+// lockSellerProduct locks the product row for a change only its seller may
+// make while the product is a draft or its auction is still open.
+func lockSellerProduct(ctx context.Context, qtx *pgstore.Queries, productId, sellerId uuid.UUID) (pgstore.Product, error) {
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFound
+		}
+
+		return pgstore.Product{}, err
+	}
+
+	if product.SellerID != sellerId {
+		return pgstore.Product{}, ErrNotProductSeller
+	}
+
+	if product.PublishedAt.Valid && !time.Now().Before(product.AuctionEnd) {
+		return pgstore.Product{}, ErrAuctionClosed
+	}
+
+	return product, nil
+}
+
+// deleteImageBlobs removes the files of images whose rows are already gone.
+// Relisted products share the files of the product they were cloned from,
+// so files still used by another image are kept. Failures are only logged:
+// a leftover file is harmless, while failing the request would not bring
+// the row back.
+func deleteImageBlobs(ctx context.Context, queries *pgstore.Queries, blobs blobstore.BlobStore, images []pgstore.ProductImage) {
+	keys := make([]string, 0, len(images))
+	for _, image := range images {
+		keys = append(keys, image.BlobKey)
+	}
+
+	referenced, err := queries.ListReferencedBlobKeys(ctx, keys)
+	if err != nil {
+		slog.Error("failed to check image blob references", "error", err)
+		return
+	}
+
+	for _, image := range images {
+		if slices.Contains(referenced, image.BlobKey) {
+			continue
+		}
+
+		for _, key := range []string{image.BlobKey, image.ThumbnailKey} {
+			if err := blobs.Delete(ctx, key); err != nil {
+				slog.Error("failed to delete image blob", "key", key, "error", err)
+			}
+		}
+	}
+}
+
+// makeThumbnail decodes an uploaded image and encodes its JPEG thumbnail,
+// waiting for a free decode slot so concurrent uploads cannot exhaust the
+// memory.
+func makeThumbnail(ctx context.Context, data []byte) ([]byte, error) {
+	select {
+	case imageDecodes <- struct{}{}:
+		defer func() { <-imageDecodes }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	var thumbnail bytes.Buffer
+	if err := jpeg.Encode(&thumbnail, Thumbnail(decoded, thumbnailSize), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+
+	return thumbnail.Bytes(), nil
+}
+
+// Thumbnail scales img down so that its longest side is at most size pixels,
+// keeping the aspect ratio. Each output pixel is the average of the source
+// pixels it covers, which avoids the aliasing of nearest neighbour sampling.
+// The source is read one row at a time, so no full size copy is made.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if srcW > size || srcH > size {
+		if srcW >= srcH {
+			dstW, dstH = size, max(1, srcH*size/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*size/srcH), size
+		}
+	}
+
+	row := image.NewRGBA(image.Rect(0, 0, srcW, 1))
+	sums := make([][5]uint64, dstW)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+
+		clear(sums)
+		for sy := y0; sy < y1; sy++ {
+			draw.Draw(row, row.Bounds(), img, image.Pt(bounds.Min.X, bounds.Min.Y+sy), draw.Src)
+
+			for x := 0; x < dstW; x++ {
+				x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+
+				sum := &sums[x]
+				for sx := x0; sx < x1; sx++ {
+					pixel := row.Pix[sx*4 : sx*4+4]
+					sum[0] += uint64(pixel[0])
+					sum[1] += uint64(pixel[1])
+					sum[2] += uint64(pixel[2])
+					sum[3] += uint64(pixel[3])
+					sum[4]++
+				}
+			}
+		}
+
+		for x, sum := range sums {
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(sum[0] / sum[4])
+			dst.Pix[offset+1] = uint8(sum[1] / sum[4])
+			dst.Pix[offset+2] = uint8(sum[2] / sum[4])
+			dst.Pix[offset+3] = uint8(sum[3] / sum[4])
+		}
+	}
+
+	return dst
+}
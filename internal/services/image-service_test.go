@@ -0,0 +1,34 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	src := image.NewYCbCr(image.Rect(10, 20, 810, 420), image.YCbCrSubsampleRatio420)
+	for i := range src.Y {
+		src.Y[i] = 200
+	}
+	for i := range src.Cb {
+		src.Cb[i], src.Cr[i] = 128, 128
+	}
+
+	thumb := Thumbnail(src, 320)
+	if got := thumb.Bounds(); got.Dx() != 320 || got.Dy() != 160 {
+		t.Fatalf("thumbnail bounds = %v, want 320x160", got)
+	}
+
+	want := color.RGBA{200, 200, 200, 255}
+	for _, p := range []image.Point{{0, 0}, {160, 80}, {319, 159}} {
+		if got := thumb.At(p.X, p.Y); got != want {
+			t.Errorf("pixel %v = %v, want %v", p, got, want)
+		}
+	}
+
+	small := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	if got := Thumbnail(small, 320).Bounds(); got.Dx() != 200 || got.Dy() != 100 {
+		t.Errorf("small image bounds = %v, want 200x100", got)
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	productreq "github.com/gregoryAlvim/gobid/internal/usecase/product"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	ImportStatusCreated = "created"
+	ImportStatusValid   = "valid"
+	ImportStatusInvalid = "invalid"
+	ImportStatusSkipped = "skipped"
+)
+
+type ImportService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewImportService(pool *pgxpool.Pool) ImportService {
+	return ImportService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// ImportOptions controls a bulk import. With a zero ChunkSize the rows are
+// created in a single transaction, and nothing is created unless every row
+// is valid; otherwise each chunk of ChunkSize rows is committed on its own
+// and invalid rows are left out. DryRun runs the whole import and rolls it
+// back.
+type ImportOptions struct {
+	Publish   bool
+	DryRun    bool
+	ChunkSize int
+}
+
+// ImportRowResult is the outcome of one row. Problems has the same shape as
+// the validation errors of the single product endpoints.
+type ImportRowResult struct {
+	Line      int               `json:"line"`
+	Status    string            `json:"status"`
+	ProductID *uuid.UUID        `json:"product_id,omitempty"`
+	Problems  map[string]string `json:"problems,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Published bool              `json:"published"`
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Invalid   int               `json:"invalid"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// ImportProducts creates a product for each row on behalf of the seller, as
+// a draft or, with opts.Publish, as a published auction. Rows are checked
+// with the same rules as CreateProductReq and, when published, as a live
+// listing. Published auctions get their room from the auction scheduler.
+func (is *ImportService) ImportProducts(ctx context.Context, sellerId uuid.UUID, rows []productreq.ImportRow, opts ImportOptions) (ImportReport, error) {
+	if err := requireVerifiedEmail(ctx, is.queries, sellerId); err != nil {
+		return ImportReport{}, err
+	}
+
+	report := ImportReport{
+		DryRun:    opts.DryRun,
+		Published: opts.Publish,
+		Total:     len(rows),
+		Rows:      make([]ImportRowResult, len(rows)),
+	}
+
+	listings := make([]ProductListing, len(rows))
+	for i, row := range rows {
+		report.Rows[i] = ImportRowResult{Line: row.Line, Status: ImportStatusValid}
+
+		listing, problems := importListing(ctx, sellerId, row, opts.Publish)
+		if len(problems) > 0 {
+			report.Rows[i].Status = ImportStatusInvalid
+			report.Rows[i].Problems = problems
+			continue
+		}
+
+		listings[i] = listing
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(rows)
+	}
+
+	for start := 0; start < len(rows); start += chunkSize {
+		end := min(start+chunkSize, len(rows))
+		if err := is.importChunk(ctx, listings[start:end], report.Rows[start:end], opts); err != nil {
+			return ImportReport{}, err
+		}
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case ImportStatusCreated:
+			report.Created++
+		case ImportStatusInvalid:
+			report.Invalid++
+		}
+	}
+
+	return report, nil
+}
+
+// importListing validates a row and turns it into a listing.
+func importListing(ctx context.Context, sellerId uuid.UUID, row productreq.ImportRow, publish bool) (ProductListing, map[string]string) {
+	if len(row.Problems) > 0 {
+		return ProductListing{}, row.Problems
+	}
+
+	if problems := row.Req.Valid(ctx); len(problems) > 0 {
+		return ProductListing{}, problems
+	}
+
+	// Both prices were checked by Valid, they parse.
+	basePrice, _ := money.Parse(row.Req.BasePrice.String(), row.Req.Currency)
+
+	listing := ProductListing{
+		SellerID:    sellerId,
+		Name:        row.Req.ProductName,
+		Description: row.Req.Description,
+		BasePrice:   basePrice,
+		StartsAt:    row.Req.StartsAt,
+		AuctionEnd:  row.Req.AuctionEnd,
+		CategoryID:  row.Req.CategoryID,
+		Tags:        row.Req.Tags,
+		Quantity:    row.Req.Quantity,
+		Pricing:     row.Req.Pricing,
+	}
+
+	if row.Req.ReservePrice != "" {
+		reservePrice, _ := money.Parse(row.Req.ReservePrice.String(), row.Req.Currency)
+		listing.ReservePrice = &reservePrice
+	}
+
+	if publish {
+		if problems := listing.withDefaults().rules().Valid(ctx); len(problems) > 0 {
+			return ProductListing{}, problems
+		}
+	}
+
+	return listing, nil
+}
+
+// importChunk creates the valid rows of a chunk in one transaction. Each row
+// runs in a savepoint, so a row the database turns down does not take the
+// others with it.
+func (is *ImportService) importChunk(ctx context.Context, listings []ProductListing, results []ImportRowResult, opts ImportOptions) error {
+	tx, err := is.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	for i := range results {
+		if results[i].Status == ImportStatusInvalid {
+			continue
+		}
+
+		productId, problems, err := importRow(ctx, tx, is.queries, listings[i], opts.Publish)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) > 0 {
+			results[i].Status = ImportStatusInvalid
+			results[i].Problems = problems
+			continue
+		}
+
+		results[i].ProductID = &productId
+	}
+
+	atomic := opts.ChunkSize <= 0
+	failed := false
+	for _, result := range results {
+		failed = failed || result.Status == ImportStatusInvalid
+	}
+
+	if opts.DryRun || (atomic && failed) {
+		for i := range results {
+			if results[i].Status == ImportStatusValid {
+				results[i].ProductID = nil
+				if !opts.DryRun {
+					results[i].Status = ImportStatusSkipped
+				}
+			}
+		}
+
+		return tx.Rollback(ctx)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	for i := range results {
+		if results[i].Status == ImportStatusValid {
+			results[i].Status = ImportStatusCreated
+		}
+	}
+
+	return nil
+}
+
+func importRow(ctx context.Context, tx pgx.Tx, queries *pgstore.Queries, listing ProductListing, publish bool) (uuid.UUID, map[string]string, error) {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, nil, err
+	}
+
+	defer savepoint.Rollback(ctx)
+
+	qtx := queries.WithTx(savepoint)
+
+	product, err := createDraft(ctx, qtx, listing)
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return uuid.UUID{}, map[string]string{"category_id": "must be an existing category"}, nil
+		}
+
+		return uuid.UUID{}, nil, err
+	}
+
+	if publish {
+		if product, err = publishDraft(ctx, qtx, product); err != nil {
+			var listingErr *ListingError
+			if errors.As(err, &listingErr) {
+				return uuid.UUID{}, listingErr.Problems, nil
+			}
+
+			return uuid.UUID{}, nil, err
+		}
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return uuid.UUID{}, nil, err
+	}
+
+	return product.ID, nil, nil
+}
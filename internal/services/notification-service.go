@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewNotificationService(pool *pgxpool.Pool) NotificationService {
+	return NotificationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	ProductID *uuid.UUID `json:"product_id"`
+	Kind      string     `json:"kind"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newNotification(row pgstore.Notification) Notification {
+	notification := Notification{
+		ID:        row.ID,
+		ProductID: nullableUUID(row.ProductID),
+		Kind:      row.Kind,
+		Subject:   row.Subject,
+		Body:      row.Body,
+		CreatedAt: row.CreatedAt,
+	}
+
+	if row.ReadAt.Valid {
+		notification.ReadAt = &row.ReadAt.Time
+	}
+
+	return notification
+}
+
+type Inbox struct {
+	Unread        int64          `json:"unread"`
+	Notifications []Notification `json:"notifications"`
+}
+
+// ListNotifications returns the user's in-app notifications, newest first.
+func (ns *NotificationService) ListNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, limit int32) (Inbox, error) {
+	rows, err := ns.queries.ListNotificationsByUserId(ctx, pgstore.ListNotificationsByUserIdParams{
+		UserID:     userId,
+		UnreadOnly: unreadOnly,
+		Limit:      limit,
+	})
+	if err != nil {
+		return Inbox{}, err
+	}
+
+	unread, err := ns.queries.CountUnreadNotifications(ctx, userId)
+	if err != nil {
+		return Inbox{}, err
+	}
+
+	inbox := Inbox{Unread: unread, Notifications: make([]Notification, 0, len(rows))}
+	for _, row := range rows {
+		inbox.Notifications = append(inbox.Notifications, newNotification(row))
+	}
+
+	return inbox, nil
+}
+
+// MarkNotificationRead marks one of the user's notifications as read.
+// Notifications of other users are reported as not found.
+func (ns *NotificationService) MarkNotificationRead(ctx context.Context, userId, notificationId uuid.UUID) (Notification, error) {
+	row, err := ns.queries.MarkNotificationRead(ctx, pgstore.MarkNotificationReadParams{ID: notificationId, UserID: userId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Notification{}, ErrNotificationNotFound
+		}
+
+		return Notification{}, err
+	}
+
+	return newNotification(row), nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/mailer"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+const passwordResetTTL = 30 * time.Minute
+
+// PasswordResetService lets users who forgot their password set a new one
+// through a link sent to their email. Reset tokens are random, stored as
+// their SHA-256 and work once.
+type PasswordResetService struct {
+	pool     *pgxpool.Pool
+	queries  *pgstore.Queries
+	users    UserService
+	mailer   mailer.Mailer
+	resetURL string
+}
+
+// NewPasswordResetService creates the service. The emailed link is resetURL
+// with the token added as the "token" query parameter.
+func NewPasswordResetService(pool *pgxpool.Pool, users UserService, m mailer.Mailer, resetURL string) PasswordResetService {
+	return PasswordResetService{
+		pool:     pool,
+		queries:  pgstore.New(pool),
+		users:    users,
+		mailer:   m,
+		resetURL: resetURL,
+	}
+}
+
+// RequestReset emails a reset link to the user registered with email. An
+// unknown email is not an error, so callers cannot tell whether it exists.
+func (prs *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := prs.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := prs.queries.DeleteStalePasswordResets(ctx, user.ID); err != nil {
+		return err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	args := pgstore.CreatePasswordResetParams{
+		UserID:    user.ID,
+		TokenHash: hashResetToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+
+	if err := prs.queries.CreatePasswordReset(ctx, args); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(prs.resetURL)
+	if err != nil {
+		return err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return prs.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your gobid account. If it was you, open the link below within %s:\n\n%s\n\nIf it was not, you can ignore this email; your password stays the same.\n",
+			user.UserName, passwordResetTTL, link),
+	})
+}
+
+// ResetPassword uses up token and sets password as the new password of its
+// user, returning the user id. Every other reset token of the user stops
+// working too, and so do their personal access tokens: a reset usually
+// means the account was at risk, and tokens created by whoever got in would
+// outlive the new password. All of their sessions end as well, since the
+// session generation of the user moves on in the same transaction.
+func (prs *PasswordResetService) ResetPassword(ctx context.Context, token, password string) (uuid.UUID, error) {
+	tx, err := prs.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := prs.queries.WithTx(tx)
+
+	userId, err := qtx.UsePasswordReset(ctx, hashResetToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrInvalidResetToken
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	if err := qtx.InvalidatePasswordResets(ctx, userId); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := prs.users.setPassword(ctx, qtx, userId, password); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := qtx.DeleteUserAccessTokens(ctx, userId); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if _, err := qtx.BumpUserSessionGeneration(ctx, userId); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return userId, nil
+}
+
+func hashResetToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -3,50 +3,206 @@ package services
 import (
 	"context"
 	"errors"
+	"html"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/store/blobstore"
 	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	productreq "github.com/gregoryAlvim/gobid/internal/usecase/product"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrProductNotFound = errors.New("product not found")
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrNotProductSeller = errors.New("only the seller can change this product")
+	ErrProductHasBids   = errors.New("the product already has bids and can no longer be changed")
+	ErrProductPublished = errors.New("the product is already published, this can only be changed while it is a draft")
+	ErrProductSold      = errors.New("the product was sold and cannot be relisted")
+	ErrProductRelisted  = errors.New("the product has already been relisted")
+)
+
+// ListingError is returned when a product does not meet the rules to be
+// live. Problems maps each field to what is wrong with it.
+type ListingError struct {
+	Problems map[string]string
+}
+
+func (e *ListingError) Error() string {
+	return "the product is not ready to be published"
+}
+
+const (
+	ProductSortEndingSoon = "ending_soon"
+	ProductSortNewest     = "newest"
+	ProductSortPriceAsc   = "price_asc"
+	ProductSortPriceDesc  = "price_desc"
+)
+
+const (
+	AuctionStatusDraft    = "draft"
+	AuctionStatusUpcoming = "upcoming"
+	AuctionStatusAll      = "all"
+)
+
+// productStatus is like auctionStatus but also knows about auctions that
+// have not started yet.
+func productStatus(startsAt, auctionEnd time.Time) string {
+	if time.Now().Before(startsAt) {
+		return AuctionStatusUpcoming
+	}
+
+	return auctionStatus(auctionEnd)
+}
 
 type ProductService struct {
 	pool    *pgxpool.Pool
 	queries *pgstore.Queries
+	blobs   blobstore.BlobStore
 }
 
-func NewProductService(pool *pgxpool.Pool) ProductService {
+func NewProductService(pool *pgxpool.Pool, blobs blobstore.BlobStore) ProductService {
 	return ProductService{
 		pool:    pool,
 		queries: pgstore.New(pool),
+		blobs:   blobs,
 	}
 }
 
-func (ps *ProductService) CreateProduct(
-	ctx context.Context,
-	sellerId uuid.UUID,
-	productName,
-	description string,
-	base_price float64,
-	auction_end time.Time,
-) (uuid.UUID, error) {
-	args := pgstore.CreateProductParams{
-		SellerID:    sellerId,
-		ProductName: productName,
-		Description: description,
-		BasePrice:   base_price,
-		AuctionEnd:  auction_end,
+// ProductListing describes a product put up for auction. Quantity is the
+// number of identical units for sale and Pricing how the winners of a
+// multi-unit auction pay, PricingUniform or PricingPayAsBid. A zero StartsAt
+// starts the auction as soon as it is published.
+type ProductListing struct {
+	SellerID     uuid.UUID
+	Name         string
+	Description  string
+	BasePrice    money.Money
+	ReservePrice *money.Money
+	StartsAt     time.Time
+	AuctionEnd   time.Time
+	CategoryID   uuid.UUID
+	Tags         []string
+	Quantity     int32
+	Pricing      string
+}
+
+// CreateProduct saves a draft. Drafts are only visible to their seller and
+// have no auction until PublishProduct is called.
+func (ps *ProductService) CreateProduct(ctx context.Context, listing ProductListing) (pgstore.Product, error) {
+	if err := requireVerifiedEmail(ctx, ps.queries, listing.SellerID); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
 	}
 
-	id, err := ps.queries.CreateProduct(ctx, args)
+	defer tx.Rollback(ctx)
+
+	product, err := createDraft(ctx, ps.queries.WithTx(tx), listing)
 	if err != nil {
-		return uuid.UUID{}, err
+		return pgstore.Product{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
 	}
 
-	return id, nil
+	return product, nil
+}
+
+// withDefaults fills the fields a listing may leave out: one unit, uniform
+// pricing and an auction that starts right away.
+func (l ProductListing) withDefaults() ProductListing {
+	l.Quantity = max(l.Quantity, 1)
+
+	if l.Pricing == "" {
+		l.Pricing = PricingUniform
+	}
+
+	if l.StartsAt.IsZero() {
+		l.StartsAt = time.Now()
+	}
+
+	return l
+}
+
+// rules returns the listing as checked by the rules of a live product.
+func (l ProductListing) rules() productreq.Listing {
+	return productreq.Listing{
+		ProductName:  l.Name,
+		Description:  l.Description,
+		BasePrice:    l.BasePrice,
+		ReservePrice: l.ReservePrice,
+		StartsAt:     l.StartsAt,
+		AuctionEnd:   l.AuctionEnd,
+		Tags:         l.Tags,
+		Quantity:     l.Quantity,
+		Pricing:      l.Pricing,
+	}
+}
+
+func createDraft(ctx context.Context, qtx *pgstore.Queries, listing ProductListing) (pgstore.Product, error) {
+	if listing.CategoryID != uuid.Nil {
+		if _, err := qtx.GetCategoryById(ctx, listing.CategoryID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.Product{}, ErrCategoryNotFound
+			}
+
+			return pgstore.Product{}, err
+		}
+	}
+
+	listing = listing.withDefaults()
+
+	product, err := qtx.CreateProduct(ctx, pgstore.CreateProductParams{
+		SellerID:     listing.SellerID,
+		ProductName:  listing.Name,
+		Description:  listing.Description,
+		BasePrice:    listing.BasePrice.Amount,
+		AuctionEnd:   listing.AuctionEnd,
+		Currency:     listing.BasePrice.Currency,
+		CategoryID:   optionalUUID(listing.CategoryID),
+		Quantity:     listing.Quantity,
+		Pricing:      listing.Pricing,
+		StartsAt:     listing.StartsAt,
+		ReservePrice: reserveAmount(listing.ReservePrice),
+	})
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if tags := NormalizeTags(listing.Tags); len(tags) > 0 {
+		if err := qtx.AddProductTags(ctx, pgstore.AddProductTagsParams{ProductID: product.ID, Tags: tags}); err != nil {
+			return pgstore.Product{}, err
+		}
+	}
+
+	return product, nil
+}
+
+// NormalizeTags lowercases and trims tags, dropping blanks and duplicates so
+// that "Vintage" and "vintage " end up as the same tag.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
 }
 
 func (ps *ProductService) GetProductById(ctx context.Context, productId uuid.UUID) (pgstore.Product, error) {
@@ -61,3 +217,1051 @@ func (ps *ProductService) GetProductById(ctx context.Context, productId uuid.UUI
 
 	return product, nil
 }
+
+func BasePrice(product pgstore.Product) money.Money {
+	return money.New(product.BasePrice, product.Currency)
+}
+
+// ReservePrice returns the reserve of the product, nil when it has none.
+func ReservePrice(product pgstore.Product) *money.Money {
+	if !product.ReservePrice.Valid {
+		return nil
+	}
+
+	reservePrice := money.New(product.ReservePrice.Int64, product.Currency)
+	return &reservePrice
+}
+
+func reserveAmount(reservePrice *money.Money) pgtype.Int8 {
+	if reservePrice == nil || reservePrice.Amount == 0 {
+		return pgtype.Int8{}
+	}
+
+	return pgtype.Int8{Int64: reservePrice.Amount, Valid: true}
+}
+
+// RelistPolicy is how many more times an unsold auction is relisted when it
+// is finalized, and by how many percent its base price drops each time.
+type RelistPolicy struct {
+	MaxRelists       int32 `json:"max_relists"`
+	PriceDropPercent int32 `json:"price_drop_percent"`
+}
+
+// ProductDetails is a product as shown in the catalog, with the state of its
+// auction. Only the seller sees the amount of the reserve and the relist
+// policy; everyone else only learns whether there is a reserve.
+type ProductDetails struct {
+	ID           uuid.UUID      `json:"id"`
+	SellerID     uuid.UUID      `json:"seller_id"`
+	ProductName  string         `json:"product_name"`
+	Description  string         `json:"description"`
+	BasePrice    money.Money    `json:"base_price"`
+	CurrentPrice money.Money    `json:"current_price"`
+	BidCount     int32          `json:"bid_count"`
+	WatchCount   int32          `json:"watch_count"`
+	Quantity     int32          `json:"quantity"`
+	Pricing      string         `json:"pricing"`
+	BidHold      int32          `json:"bid_hold_percent"`
+	HasReserve   bool           `json:"has_reserve"`
+	ReservePrice *money.Money   `json:"reserve_price,omitempty"`
+	AutoRelist   *RelistPolicy  `json:"auto_relist,omitempty"`
+	RelistedFrom *uuid.UUID     `json:"relisted_from_id"`
+	Status       string         `json:"status"`
+	CategoryID   *uuid.UUID     `json:"category_id"`
+	Tags         []string       `json:"tags"`
+	Images       []ProductImage `json:"images"`
+	StartsAt     time.Time      `json:"starts_at"`
+	AuctionEnd   time.Time      `json:"auction_end"`
+	PublishedAt  *time.Time     `json:"published_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+func newProductDetails(row pgstore.GetProductWithStatsByIdRow) ProductDetails {
+	details := ProductDetails{
+		ID:           row.ID,
+		SellerID:     row.SellerID,
+		ProductName:  row.ProductName,
+		Description:  row.Description,
+		BasePrice:    money.New(row.BasePrice, row.Currency),
+		CurrentPrice: money.New(row.CurrentPrice, row.Currency),
+		BidCount:     row.BidCount,
+		WatchCount:   row.WatchCount,
+		Quantity:     row.Quantity,
+		Pricing:      row.Pricing,
+		BidHold:      row.BidHoldPercent,
+		HasReserve:   row.ReservePrice.Valid,
+		RelistedFrom: nullableUUID(row.RelistedFromID),
+		Status:       productStatus(row.StartsAt, row.AuctionEnd),
+		CategoryID:   nullableUUID(row.CategoryID),
+		Tags:         []string{},
+		Images:       []ProductImage{},
+		StartsAt:     row.StartsAt,
+		AuctionEnd:   row.AuctionEnd,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+
+	if row.PublishedAt.Valid {
+		details.PublishedAt = &row.PublishedAt.Time
+	} else {
+		details.Status = AuctionStatusDraft
+	}
+
+	return details
+}
+
+// revealToSeller fills the fields of details that only the seller sees.
+func revealToSeller(details *ProductDetails, row pgstore.GetProductWithStatsByIdRow) {
+	if row.ReservePrice.Valid {
+		reservePrice := money.New(row.ReservePrice.Int64, row.Currency)
+		details.ReservePrice = &reservePrice
+	}
+
+	details.AutoRelist = &RelistPolicy{MaxRelists: row.AutoRelistRemaining, PriceDropPercent: row.AutoRelistDropPercent}
+}
+
+type ProductPage struct {
+	Products []ProductDetails `json:"products"`
+	Page     int32            `json:"page"`
+	Limit    int32            `json:"limit"`
+	Total    int64            `json:"total"`
+}
+
+// ProductFilter narrows down the catalog. Status is one of the AuctionStatus
+// constants; a category also matches the products of its subcategories.
+// CategoryID uuid.Nil and an empty Tag match every product.
+type ProductFilter struct {
+	Status     string
+	CategoryID uuid.UUID
+	Tag        string
+}
+
+// ListProducts returns one page of the catalog. Sort is one of the
+// ProductSort constants. Prices are compared in minor units regardless of
+// their currency.
+func (ps *ProductService) ListProducts(ctx context.Context, filter ProductFilter, sort string, page, limit int32) (ProductPage, error) {
+	categoryId := optionalUUID(filter.CategoryID)
+	tag := pgtype.Text{String: strings.ToLower(strings.TrimSpace(filter.Tag)), Valid: filter.Tag != ""}
+
+	rows, err := ps.queries.ListProducts(ctx, pgstore.ListProductsParams{
+		Status:     filter.Status,
+		CategoryID: categoryId,
+		Tag:        tag,
+		Sort:       sort,
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	})
+	if err != nil {
+		return ProductPage{}, err
+	}
+
+	total, err := ps.queries.CountProducts(ctx, pgstore.CountProductsParams{
+		Status:     filter.Status,
+		CategoryID: categoryId,
+		Tag:        tag,
+	})
+	if err != nil {
+		return ProductPage{}, err
+	}
+
+	products := make([]ProductDetails, 0, len(rows))
+	for _, row := range rows {
+		products = append(products, newProductDetails(pgstore.GetProductWithStatsByIdRow(row)))
+	}
+
+	if err := loadCollections(ctx, ps.queries, products); err != nil {
+		return ProductPage{}, err
+	}
+
+	return ProductPage{Products: products, Page: page, Limit: limit, Total: total}, nil
+}
+
+// loadCollections fills in the tags and images of the given products with
+// one query for each, whatever the number of products.
+func loadCollections(ctx context.Context, queries *pgstore.Queries, products []ProductDetails) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	tags, err := queries.ListProductTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	images, err := queries.ListProductImages(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	tagsByProduct := make(map[uuid.UUID][]string, len(products))
+	for _, tag := range tags {
+		tagsByProduct[tag.ProductID] = append(tagsByProduct[tag.ProductID], tag.Tag)
+	}
+
+	imagesByProduct := make(map[uuid.UUID][]ProductImage, len(products))
+	for _, image := range images {
+		imagesByProduct[image.ProductID] = append(imagesByProduct[image.ProductID], newProductImage(image))
+	}
+
+	for i := range products {
+		if tags, ok := tagsByProduct[products[i].ID]; ok {
+			products[i].Tags = tags
+		}
+
+		if images, ok := imagesByProduct[products[i].ID]; ok {
+			products[i].Images = images
+		}
+	}
+
+	return nil
+}
+
+// GetProductDetails returns a product as seen by viewerId. Drafts are only
+// found by their seller.
+func (ps *ProductService) GetProductDetails(ctx context.Context, productId, viewerId uuid.UUID) (ProductDetails, error) {
+	row, err := ps.queries.GetProductWithStatsById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ProductDetails{}, ErrProductNotFound
+		}
+
+		return ProductDetails{}, err
+	}
+
+	if !row.PublishedAt.Valid && row.SellerID != viewerId {
+		return ProductDetails{}, ErrProductNotFound
+	}
+
+	products := []ProductDetails{newProductDetails(row)}
+	if row.SellerID == viewerId {
+		revealToSeller(&products[0], row)
+	}
+
+	if err := loadCollections(ctx, ps.queries, products); err != nil {
+		return ProductDetails{}, err
+	}
+
+	return products[0], nil
+}
+
+// ProductChanges holds the fields of a product update; nil fields are kept.
+// A zero ReservePrice removes the reserve, a CategoryID of uuid.Nil removes
+// the product from its category and Tags replaces every tag of the product.
+type ProductChanges struct {
+	ProductName  *string
+	Description  *string
+	BasePrice    *money.Money
+	ReservePrice *money.Money
+	StartsAt     *time.Time
+	AuctionEnd   *time.Time
+	CategoryID   *uuid.UUID
+	Tags         *[]string
+	Quantity     *int32
+	Pricing      *string
+}
+
+// lockEditableProduct locks the product row and makes sure the seller can
+// still change it, i.e. nobody has bid on it yet. Holding the lock keeps
+// PlaceBid out until the transaction ends.
+func lockEditableProduct(ctx context.Context, qtx *pgstore.Queries, productId, sellerId uuid.UUID) (pgstore.Product, error) {
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFound
+		}
+
+		return pgstore.Product{}, err
+	}
+
+	if product.SellerID != sellerId {
+		return pgstore.Product{}, ErrNotProductSeller
+	}
+
+	bids, err := qtx.CountBidsByProductId(ctx, productId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if bids > 0 {
+		return pgstore.Product{}, ErrProductHasBids
+	}
+
+	return product, nil
+}
+
+// UpdateProduct changes a product that has not received any bid yet. Drafts
+// can be changed freely; the start and the rules of a published product are
+// fixed, and the result must still meet every rule of a live listing.
+func (ps *ProductService) UpdateProduct(ctx context.Context, productId, sellerId uuid.UUID, changes ProductChanges) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+
+	product, err := lockEditableProduct(ctx, qtx, productId, sellerId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	published := product.PublishedAt.Valid
+
+	if published && !time.Now().Before(product.AuctionEnd) {
+		return pgstore.Product{}, ErrAuctionClosed
+	}
+
+	if published && (changes.StartsAt != nil || changes.Quantity != nil || changes.Pricing != nil) {
+		return pgstore.Product{}, ErrProductPublished
+	}
+
+	args := pgstore.UpdateProductParams{
+		ID:           product.ID,
+		ProductName:  product.ProductName,
+		Description:  product.Description,
+		BasePrice:    product.BasePrice,
+		Currency:     product.Currency,
+		AuctionEnd:   product.AuctionEnd,
+		StartsAt:     product.StartsAt,
+		CategoryID:   product.CategoryID,
+		Quantity:     product.Quantity,
+		Pricing:      product.Pricing,
+		ReservePrice: product.ReservePrice,
+	}
+
+	if changes.ProductName != nil {
+		args.ProductName = *changes.ProductName
+	}
+
+	if changes.Description != nil {
+		args.Description = *changes.Description
+	}
+
+	if changes.BasePrice != nil {
+		args.BasePrice = changes.BasePrice.Amount
+		args.Currency = changes.BasePrice.Currency
+	}
+
+	// The reserve is stored in the currency of the product, so it can
+	// neither come in another currency nor outlive a change of currency.
+	if changes.ReservePrice != nil {
+		if changes.ReservePrice.Amount != 0 && changes.ReservePrice.Currency != args.Currency {
+			return pgstore.Product{}, &ListingError{Problems: map[string]string{"reserve_price": "must be in the currency of the base price"}}
+		}
+
+		args.ReservePrice = reserveAmount(changes.ReservePrice)
+	} else if product.ReservePrice.Valid && args.Currency != product.Currency {
+		return pgstore.Product{}, &ListingError{Problems: map[string]string{"reserve_price": "must be sent again, or removed, when the currency changes"}}
+	}
+
+	if changes.StartsAt != nil {
+		args.StartsAt = *changes.StartsAt
+	}
+
+	if changes.AuctionEnd != nil {
+		args.AuctionEnd = *changes.AuctionEnd
+	}
+
+	if changes.Quantity != nil {
+		args.Quantity = *changes.Quantity
+	}
+
+	if changes.Pricing != nil {
+		args.Pricing = *changes.Pricing
+	}
+
+	if changes.CategoryID != nil {
+		if *changes.CategoryID != uuid.Nil {
+			if _, err := qtx.GetCategoryById(ctx, *changes.CategoryID); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return pgstore.Product{}, ErrCategoryNotFound
+				}
+
+				return pgstore.Product{}, err
+			}
+		}
+
+		args.CategoryID = optionalUUID(*changes.CategoryID)
+	}
+
+	if changes.Tags != nil {
+		if err := qtx.DeleteProductTags(ctx, product.ID); err != nil {
+			return pgstore.Product{}, err
+		}
+
+		if tags := NormalizeTags(*changes.Tags); len(tags) > 0 {
+			if err := qtx.AddProductTags(ctx, pgstore.AddProductTagsParams{ProductID: product.ID, Tags: tags}); err != nil {
+				return pgstore.Product{}, err
+			}
+		}
+	}
+
+	updated, err := qtx.UpdateProduct(ctx, args)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if published {
+		problems, err := listingProblems(ctx, qtx, updated)
+		if err != nil {
+			return pgstore.Product{}, err
+		}
+
+		// A running auction may be closer to its end than a new listing
+		// is allowed to be; that only matters when the end moves.
+		if changes.AuctionEnd == nil {
+			delete(problems, "auction_end")
+		}
+
+		if len(problems) > 0 {
+			return pgstore.Product{}, &ListingError{Problems: problems}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	return updated, nil
+}
+
+// listingProblems runs the rules a product must meet to be live.
+func listingProblems(ctx context.Context, qtx *pgstore.Queries, product pgstore.Product) (map[string]string, error) {
+	tags, err := qtx.ListProductTags(ctx, []uuid.UUID{product.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	listing := productreq.Listing{
+		ProductName:  product.ProductName,
+		Description:  product.Description,
+		BasePrice:    BasePrice(product),
+		ReservePrice: ReservePrice(product),
+		StartsAt:     product.StartsAt,
+		AuctionEnd:   product.AuctionEnd,
+		Quantity:     product.Quantity,
+		Pricing:      product.Pricing,
+	}
+
+	for _, tag := range tags {
+		listing.Tags = append(listing.Tags, tag.Tag)
+	}
+
+	return listing.Valid(ctx), nil
+}
+
+// DraftPreview shows a draft the way it will be listed, along with what
+// still keeps it from being published.
+type DraftPreview struct {
+	Product  ProductDetails    `json:"product"`
+	Ready    bool              `json:"ready"`
+	Problems map[string]string `json:"problems"`
+}
+
+// PreviewDraft validates a draft of the seller without publishing it.
+func (ps *ProductService) PreviewDraft(ctx context.Context, productId, sellerId uuid.UUID) (DraftPreview, error) {
+	product, err := ps.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return DraftPreview{}, ErrProductNotFound
+		}
+
+		return DraftPreview{}, err
+	}
+
+	if product.SellerID != sellerId {
+		return DraftPreview{}, ErrNotProductSeller
+	}
+
+	if product.PublishedAt.Valid {
+		return DraftPreview{}, ErrProductPublished
+	}
+
+	details, err := ps.GetProductDetails(ctx, productId, sellerId)
+	if err != nil {
+		return DraftPreview{}, err
+	}
+
+	problems, err := listingProblems(ctx, ps.queries, product)
+	if err != nil {
+		return DraftPreview{}, err
+	}
+
+	if problems == nil {
+		problems = map[string]string{}
+	}
+
+	return DraftPreview{Product: details, Ready: len(problems) == 0, Problems: problems}, nil
+}
+
+// PublishProduct takes a draft live once it meets every rule of a listing.
+// The auction starts right away, or at its StartsAt if that is still ahead;
+// it is up to the caller to open the room when the auction starts.
+func (ps *ProductService) PublishProduct(ctx context.Context, productId, sellerId uuid.UUID) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFound
+		}
+
+		return pgstore.Product{}, err
+	}
+
+	if product.SellerID != sellerId {
+		return pgstore.Product{}, ErrNotProductSeller
+	}
+
+	if product.PublishedAt.Valid {
+		return pgstore.Product{}, ErrProductPublished
+	}
+
+	published, err := publishDraft(ctx, qtx, product)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	return published, nil
+}
+
+// publishDraft publishes a draft that meets every rule of a listing. A start
+// that already passed moves to now.
+func publishDraft(ctx context.Context, qtx *pgstore.Queries, product pgstore.Product) (pgstore.Product, error) {
+	problems, err := listingProblems(ctx, qtx, product)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if len(problems) > 0 {
+		return pgstore.Product{}, &ListingError{Problems: problems}
+	}
+
+	startsAt := product.StartsAt
+	if now := time.Now(); startsAt.Before(now) {
+		startsAt = now
+	}
+
+	return qtx.PublishProduct(ctx, pgstore.PublishProductParams{ID: product.ID, StartsAt: startsAt})
+}
+
+// ListDrafts returns the seller's drafts, most recently edited first.
+func (ps *ProductService) ListDrafts(ctx context.Context, sellerId uuid.UUID) ([]ProductDetails, error) {
+	rows, err := ps.queries.ListDraftsBySellerId(ctx, sellerId)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]ProductDetails, 0, len(rows))
+	for _, row := range rows {
+		details := newProductDetails(pgstore.GetProductWithStatsByIdRow(row))
+		revealToSeller(&details, pgstore.GetProductWithStatsByIdRow(row))
+		products = append(products, details)
+	}
+
+	if err := loadCollections(ctx, ps.queries, products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// ListStartedProducts returns the published products whose auction is
+// running right now.
+func (ps *ProductService) ListStartedProducts(ctx context.Context) ([]pgstore.Product, error) {
+	return ps.queries.ListStartedProducts(ctx)
+}
+
+// RelistOptions changes a relisted auction. A nil BasePrice keeps the base
+// price of the finished auction and a zero AuctionEnd gives the new auction
+// the same duration.
+type RelistOptions struct {
+	BasePrice  *money.Money
+	AuctionEnd time.Time
+}
+
+// RelistProduct clones a finished product that did not sell into a new
+// auction that starts right away. The base price may only go down. The
+// clone keeps the tags, images, reserve and relist policy of the original
+// and links back to it; a product can only be relisted once.
+func (ps *ProductService) RelistProduct(ctx context.Context, productId, sellerId uuid.UUID, opts RelistOptions) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFound
+		}
+
+		return pgstore.Product{}, err
+	}
+
+	if product.SellerID != sellerId {
+		return pgstore.Product{}, ErrNotProductSeller
+	}
+
+	if !product.FinalizedAt.Valid {
+		return pgstore.Product{}, ErrAuctionNotEnded
+	}
+
+	if product.IsSold {
+		return pgstore.Product{}, ErrProductSold
+	}
+
+	if _, err := qtx.GetRelistOf(ctx, productId); err == nil {
+		return pgstore.Product{}, ErrProductRelisted
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return pgstore.Product{}, err
+	}
+
+	basePrice := product.BasePrice
+	if opts.BasePrice != nil {
+		switch {
+		case opts.BasePrice.Currency != product.Currency:
+			return pgstore.Product{}, &ListingError{Problems: map[string]string{"base_price": "must be in the currency of the product"}}
+		case opts.BasePrice.Amount > product.BasePrice:
+			return pgstore.Product{}, &ListingError{Problems: map[string]string{"base_price": "cannot be higher than the previous base price"}}
+		}
+
+		basePrice = opts.BasePrice.Amount
+	}
+
+	auctionEnd := opts.AuctionEnd
+	if auctionEnd.IsZero() {
+		auctionEnd = time.Now().Add(product.AuctionEnd.Sub(product.StartsAt))
+	}
+
+	relisted, err := relistProduct(ctx, qtx, product, basePrice, auctionEnd, product.AutoRelistRemaining)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	problems, err := listingProblems(ctx, qtx, relisted)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if len(problems) > 0 {
+		return pgstore.Product{}, &ListingError{Problems: problems}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	return relisted, nil
+}
+
+// relistProduct creates the published clone of product along with copies
+// of its tags and images. The image rows point at the same files.
+func relistProduct(ctx context.Context, qtx *pgstore.Queries, product pgstore.Product, basePrice int64, auctionEnd time.Time, relistsLeft int32) (pgstore.Product, error) {
+	relisted, err := qtx.CreateRelistedProduct(ctx, pgstore.CreateRelistedProductParams{
+		BasePrice:           basePrice,
+		AuctionEnd:          auctionEnd,
+		AutoRelistRemaining: relistsLeft,
+		ID:                  product.ID,
+	})
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if err := qtx.CopyProductTags(ctx, pgstore.CopyProductTagsParams{TargetID: relisted.ID, ProductID: product.ID}); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if err := qtx.CopyProductImages(ctx, pgstore.CopyProductImagesParams{TargetID: relisted.ID, ProductID: product.ID}); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	return relisted, nil
+}
+
+// SetRelistPolicy sets the auto-relist policy of a product whose auction is
+// not finalized yet. It can be changed even after bids came in, as it only
+// matters if the product does not sell.
+func (ps *ProductService) SetRelistPolicy(ctx context.Context, productId, sellerId uuid.UUID, policy RelistPolicy) (RelistPolicy, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return RelistPolicy{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+
+	product, err := qtx.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RelistPolicy{}, ErrProductNotFound
+		}
+
+		return RelistPolicy{}, err
+	}
+
+	if product.SellerID != sellerId {
+		return RelistPolicy{}, ErrNotProductSeller
+	}
+
+	if product.FinalizedAt.Valid {
+		return RelistPolicy{}, ErrAuctionClosed
+	}
+
+	updated, err := qtx.SetProductRelistPolicy(ctx, pgstore.SetProductRelistPolicyParams{
+		ID:                    productId,
+		AutoRelistRemaining:   policy.MaxRelists,
+		AutoRelistDropPercent: policy.PriceDropPercent,
+	})
+	if err != nil {
+		return RelistPolicy{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RelistPolicy{}, err
+	}
+
+	return RelistPolicy{MaxRelists: updated.AutoRelistRemaining, PriceDropPercent: updated.AutoRelistDropPercent}, nil
+}
+
+// SetBidHold sets the share of each bid on the product that is held in the
+// bidder's wallet. Holds are placed as bids come in, so it can only change
+// before the first bid.
+func (ps *ProductService) SetBidHold(ctx context.Context, productId, sellerId uuid.UUID, percent int32) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+
+	product, err := lockEditableProduct(ctx, qtx, productId, sellerId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if product.FinalizedAt.Valid {
+		return pgstore.Product{}, ErrAuctionClosed
+	}
+
+	updated, err := qtx.SetProductBidHold(ctx, pgstore.SetProductBidHoldParams{ID: productId, BidHoldPercent: percent})
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	return updated, nil
+}
+
+// RelistEntry is one auction in the relist history of a product.
+type RelistEntry struct {
+	ProductID    uuid.UUID   `json:"product_id"`
+	RelistedFrom *uuid.UUID  `json:"relisted_from_id"`
+	BasePrice    money.Money `json:"base_price"`
+	Status       string      `json:"status"`
+	Sold         bool        `json:"sold"`
+	StartsAt     time.Time   `json:"starts_at"`
+	AuctionEnd   time.Time   `json:"auction_end"`
+	FinalizedAt  *time.Time  `json:"finalized_at"`
+}
+
+// RelistHistory returns every auction of the relist chain the product is
+// part of, from the first listing to the latest relist.
+func (ps *ProductService) RelistHistory(ctx context.Context, productId, viewerId uuid.UUID) ([]RelistEntry, error) {
+	product, err := ps.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+
+		return nil, err
+	}
+
+	if !product.PublishedAt.Valid && product.SellerID != viewerId {
+		return nil, ErrProductNotFound
+	}
+
+	rows, err := ps.queries.ListRelistChain(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]RelistEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := RelistEntry{
+			ProductID:    row.ID,
+			RelistedFrom: nullableUUID(row.RelistedFromID),
+			BasePrice:    money.New(row.BasePrice, row.Currency),
+			Status:       productStatus(row.StartsAt, row.AuctionEnd),
+			Sold:         row.IsSold,
+			StartsAt:     row.StartsAt,
+			AuctionEnd:   row.AuctionEnd,
+		}
+
+		if row.FinalizedAt.Valid {
+			entry.FinalizedAt = &row.FinalizedAt.Time
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
+// DeleteProduct removes a product that has not received any bid yet, along
+// with its image files.
+func (ps *ProductService) DeleteProduct(ctx context.Context, productId, sellerId uuid.UUID) error {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ps.queries.WithTx(tx)
+
+	if _, err := lockEditableProduct(ctx, qtx, productId, sellerId); err != nil {
+		return err
+	}
+
+	images, err := qtx.ListProductImages(ctx, []uuid.UUID{productId})
+	if err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteProduct(ctx, productId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	deleteImageBlobs(ctx, ps.queries, ps.blobs, images)
+	return nil
+}
+
+var ErrEmptySearchQuery = errors.New("the search query has no words to look for")
+
+// Search dictionaries, by the language code accepted in the API.
+var SearchLanguages = map[string]string{
+	"pt": "portuguese",
+	"en": "english",
+}
+
+// ProductSearch describes a catalog search. Zero values disable the optional
+// filters.
+type ProductSearch struct {
+	Query        string
+	Language     string
+	MinPrice     *money.Money
+	MaxPrice     *money.Money
+	Currency     string
+	Status       string
+	EndingBefore time.Time
+	Page         int32
+	Limit        int32
+}
+
+// SearchResult is a product found by a search, with its relevance and the
+// matched parts highlighted between <mark> tags.
+type SearchResult struct {
+	ProductDetails
+	Rank     float32        `json:"rank"`
+	Snippets SearchSnippets `json:"snippets"`
+}
+
+type SearchSnippets struct {
+	ProductName string `json:"product_name"`
+	Description string `json:"description"`
+}
+
+type SearchPage struct {
+	Results []SearchResult `json:"results"`
+	Page    int32          `json:"page"`
+	Limit   int32          `json:"limit"`
+}
+
+// SearchProducts runs a ranked full text search over names and descriptions.
+// Every word of the query must match, and the last letters of each word may
+// be missing, so partial words typed in a search box already find results.
+func (ps *ProductService) SearchProducts(ctx context.Context, search ProductSearch) (SearchPage, error) {
+	query := prefixQuery(search.Query)
+	if query == "" {
+		return SearchPage{}, ErrEmptySearchQuery
+	}
+
+	args := pgstore.SearchProductsParams{
+		Language: SearchLanguages[search.Language],
+		Query:    query,
+		Status:   search.Status,
+		Limit:    search.Limit,
+		Offset:   (search.Page - 1) * search.Limit,
+	}
+
+	if search.MinPrice != nil {
+		args.MinPrice = pgtype.Int8{Int64: search.MinPrice.Amount, Valid: true}
+	}
+
+	if search.MaxPrice != nil {
+		args.MaxPrice = pgtype.Int8{Int64: search.MaxPrice.Amount, Valid: true}
+	}
+
+	if search.Currency != "" {
+		args.Currency = pgtype.Text{String: search.Currency, Valid: true}
+	}
+
+	if !search.EndingBefore.IsZero() {
+		args.EndingBefore = pgtype.Timestamptz{Time: search.EndingBefore, Valid: true}
+	}
+
+	rows, err := ps.queries.SearchProducts(ctx, args)
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	// The products are loaded with the same columns and conversion as the
+	// catalog, so search results never fall behind the product details.
+	products, err := ps.queries.ListProductsWithStatsByIds(ctx, ids)
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	details := make(map[uuid.UUID]ProductDetails, len(products))
+	for _, product := range products {
+		details[product.ID] = newProductDetails(pgstore.GetProductWithStatsByIdRow(product))
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		product, ok := details[row.ID]
+		if !ok {
+			// Deleted between the two queries.
+			continue
+		}
+
+		results = append(results, SearchResult{
+			ProductDetails: product,
+			Rank:           row.Rank,
+			Snippets: SearchSnippets{
+				ProductName: highlightSnippet(row.NameSnippet),
+				Description: highlightSnippet(row.DescriptionSnippet),
+			},
+		})
+	}
+
+	found := make([]ProductDetails, len(results))
+	for i := range results {
+		found[i] = results[i].ProductDetails
+	}
+
+	if err := loadCollections(ctx, ps.queries, found); err != nil {
+		return SearchPage{}, err
+	}
+
+	for i := range results {
+		results[i].ProductDetails = found[i]
+	}
+
+	return SearchPage{Results: results, Page: search.Page, Limit: search.Limit}, nil
+}
+
+// The search marks the matched words with two private use characters
+// (chr(57344) and chr(57345) in the query) instead of HTML, because the
+// text around them is written by sellers and must be escaped first.
+const (
+	snippetStartSel = "\ue000"
+	snippetStopSel  = "\ue001"
+)
+
+// highlightSnippet escapes a snippet as HTML and only then turns the marks
+// of the search into <mark> tags. The tags always come in pairs, even when
+// a seller typed the mark characters into the text.
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+
+	var b strings.Builder
+	open := false
+	for len(escaped) > 0 {
+		start := strings.Index(escaped, snippetStartSel)
+		stop := strings.Index(escaped, snippetStopSel)
+
+		next, sel := start, snippetStartSel
+		if next < 0 || (stop >= 0 && stop < next) {
+			next, sel = stop, snippetStopSel
+		}
+
+		if next < 0 {
+			b.WriteString(escaped)
+			break
+		}
+
+		b.WriteString(escaped[:next])
+		escaped = escaped[next+len(sel):]
+
+		switch {
+		case sel == snippetStartSel && !open:
+			b.WriteString("<mark>")
+			open = true
+		case sel == snippetStopSel && open:
+			b.WriteString("</mark>")
+			open = false
+		}
+	}
+
+	if open {
+		b.WriteString("</mark>")
+	}
+
+	return b.String()
+}
+
+// prefixQuery turns free text into a tsquery that requires every word, each
+// one matched as a prefix. Anything that is not a letter or a digit is
+// dropped, so user input can never break the tsquery syntax.
+func prefixQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, word+":*")
+	}
+
+	return strings.Join(terms, " & ")
+}
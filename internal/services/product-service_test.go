@@ -0,0 +1,22 @@
+package services
+
+import "testing"
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		snippet string
+		want    string
+	}{
+		{"plain text", "plain text"},
+		{"a \ue000vintage\ue001 lamp", "a <mark>vintage</mark> lamp"},
+		{"<script>alert(1)</script> \ue000lamp\ue001", "&lt;script&gt;alert(1)&lt;/script&gt; <mark>lamp</mark>"},
+		{"<img src=x onerror=\"alert(1)\">", "&lt;img src=x onerror=&#34;alert(1)&#34;&gt;"},
+		{"\ue001stray\ue000 \ue000open", "stray<mark> open</mark>"},
+	}
+
+	for _, tt := range tests {
+		if got := highlightSnippet(tt.snippet); got != tt.want {
+			t.Errorf("highlightSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrBidNotFound                = errors.New("bid not found")
+	ErrNotBidOwner                = errors.New("only the bidder can ask to retract this bid")
+	ErrBidAlreadyVoid             = errors.New("bid has already been voided")
+	ErrRetractionAlreadyRequested = errors.New("a retraction for this bid is already pending")
+	ErrRetractionNotFound         = errors.New("retraction request not found")
+	ErrRetractionAlreadyReviewed  = errors.New("retraction request has already been reviewed")
+	ErrNotAllowedToReview         = errors.New("only the seller or an admin can review retractions")
+)
+
+const (
+	RetractionPending  = "pending"
+	RetractionApproved = "approved"
+	RetractionRejected = "rejected"
+)
+
+type RetractionService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewRetractionService(pool *pgxpool.Pool) RetractionService {
+	return RetractionService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// RetractionReview is the outcome of a reviewed retraction together with the
+// price of the auction after it, so callers can publish a price correction.
+type RetractionReview struct {
+	Retraction   pgstore.BidRetraction
+	ProductID    uuid.UUID
+	CurrentPrice money.Money
+}
+
+func (rs *RetractionService) RequestRetraction(ctx context.Context, bidId, requesterId uuid.UUID, reason string) (pgstore.BidRetraction, error) {
+	bid, err := rs.queries.GetBidById(ctx, bidId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.BidRetraction{}, ErrBidNotFound
+		}
+
+		return pgstore.BidRetraction{}, err
+	}
+
+	if bid.BidderID != requesterId {
+		return pgstore.BidRetraction{}, ErrNotBidOwner
+	}
+
+	if bid.VoidedAt.Valid {
+		return pgstore.BidRetraction{}, ErrBidAlreadyVoid
+	}
+
+	args := pgstore.CreateBidRetractionParams{
+		BidID:       bidId,
+		RequesterID: requesterId,
+		Reason:      reason,
+	}
+
+	retraction, err := rs.queries.CreateBidRetraction(ctx, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return pgstore.BidRetraction{}, ErrRetractionAlreadyRequested
+		}
+
+		return pgstore.BidRetraction{}, err
+	}
+
+	return retraction, nil
+}
+
+// Retraction is a retraction request as the reviewers see it. The bidder is
+// named by their pseudonym in the auction, like everywhere else in it.
+type Retraction struct {
+	ID         uuid.UUID  `json:"id"`
+	BidID      uuid.UUID  `json:"bid_id"`
+	Bidder     string     `json:"bidder"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (rs *RetractionService) ListRetractions(ctx context.Context, productId, userId uuid.UUID, isAdmin bool) ([]Retraction, error) {
+	product, err := rs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+
+		return nil, err
+	}
+
+	if product.SellerID != userId && !isAdmin {
+		return nil, ErrNotAllowedToReview
+	}
+
+	rows, err := rs.queries.ListBidRetractionsByProductId(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	retractions := make([]Retraction, 0, len(rows))
+	for _, row := range rows {
+		retraction := Retraction{
+			ID:        row.ID,
+			BidID:     row.BidID,
+			Bidder:    formatPseudonym(row.BidderNumber),
+			Reason:    row.Reason,
+			Status:    row.Status,
+			CreatedAt: row.CreatedAt,
+		}
+
+		if row.ReviewedAt.Valid {
+			retraction.ReviewedAt = &row.ReviewedAt.Time
+		}
+
+		retractions = append(retractions, retraction)
+	}
+
+	return retractions, nil
+}
+
+// ReviewRetraction approves or rejects a pending retraction. Approved
+// retractions void the bid instead of deleting it and count against the
+// bidder's record. Once the auction has ended its bids are final, so the
+// request can then only be rejected.
+func (rs *RetractionService) ReviewRetraction(ctx context.Context, retractionId, reviewerId uuid.UUID, reviewerIsAdmin, approve bool) (RetractionReview, error) {
+	tx, err := rs.pool.Begin(ctx)
+	if err != nil {
+		return RetractionReview{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := rs.queries.WithTx(tx)
+
+	retraction, err := qtx.GetBidRetractionByIdForUpdate(ctx, retractionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return RetractionReview{}, ErrRetractionNotFound
+		}
+
+		return RetractionReview{}, err
+	}
+
+	if retraction.Status != RetractionPending {
+		return RetractionReview{}, ErrRetractionAlreadyReviewed
+	}
+
+	bid, err := qtx.GetBidById(ctx, retraction.BidID)
+	if err != nil {
+		return RetractionReview{}, err
+	}
+
+	product, err := qtx.GetProductByIdForUpdate(ctx, bid.ProductID)
+	if err != nil {
+		return RetractionReview{}, err
+	}
+
+	if product.SellerID != reviewerId && !reviewerIsAdmin {
+		return RetractionReview{}, ErrNotAllowedToReview
+	}
+
+	status := RetractionRejected
+	if approve {
+		if product.FinalizedAt.Valid || !time.Now().Before(product.AuctionEnd) {
+			return RetractionReview{}, ErrAuctionClosed
+		}
+
+		status = RetractionApproved
+
+		if _, err := qtx.VoidBid(ctx, bid.ID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return RetractionReview{}, err
+		}
+
+		if err := qtx.IncrementUserRetractionCount(ctx, bid.BidderID); err != nil {
+			return RetractionReview{}, err
+		}
+
+		hold, err := qtx.GetActiveBidHoldByBidId(ctx, bid.ID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return RetractionReview{}, err
+		}
+
+		if err == nil {
+			if err := releaseHold(ctx, qtx, hold); err != nil {
+				return RetractionReview{}, err
+			}
+		}
+	}
+
+	reviewed, err := qtx.ReviewBidRetraction(ctx, pgstore.ReviewBidRetractionParams{
+		ID:         retraction.ID,
+		Status:     status,
+		ReviewerID: reviewerId,
+	})
+	if err != nil {
+		return RetractionReview{}, err
+	}
+
+	standing, err := qtx.ListStandingBids(ctx, product.ID)
+	if err != nil {
+		return RetractionReview{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RetractionReview{}, err
+	}
+
+	return RetractionReview{Retraction: reviewed, ProductID: product.ID, CurrentPrice: clearingPrice(standing, product)}, nil
+}
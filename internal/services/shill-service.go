@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RequestOrigin is where a request came from: the client address and the
+// device id reported by the client, if any.
+type RequestOrigin struct {
+	IP       netip.Addr
+	DeviceID string
+}
+
+var (
+	ErrShillFlagNotFound        = errors.New("shill flag not found")
+	ErrShillFlagAlreadyReviewed = errors.New("shill flag has already been reviewed")
+)
+
+const (
+	ShillFlagPending   = "pending"
+	ShillFlagDismissed = "dismissed"
+	ShillFlagConfirmed = "confirmed"
+)
+
+const (
+	ShillRuleNewAccountSingleSeller = "new_account_single_seller"
+	ShillRuleNeverWins              = "never_wins"
+	ShillRuleSellerOrigin           = "seller_origin"
+)
+
+// Thresholds used by the analyzer. Accounts younger than newAccountDays that
+// placed at least newAccountMinBids bids on one seller, with at least
+// sellerConcentration of all their bids going to that seller, are flagged.
+// Bidders who took part in neverWinsMinAuctions finalized auctions of the
+// same seller without winning a unit in any are flagged too.
+const (
+	newAccountDays       = 30
+	newAccountMinBids    = 3
+	sellerConcentration  = 0.8
+	neverWinsMinAuctions = 5
+)
+
+type ShillService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewShillService(pool *pgxpool.Pool) ShillService {
+	return ShillService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// Analyze scores the bidding patterns that usually point to shill bidding and
+// saves a flag for each suspicious bidder and seller pair. Flags that were
+// already reviewed are left alone.
+func (ss *ShillService) Analyze(ctx context.Context) error {
+	concentrated, err := ss.queries.ListNewAccountSellerConcentration(ctx, pgstore.ListNewAccountSellerConcentrationParams{
+		NewAccountDays: newAccountDays,
+		MinBids:        newAccountMinBids,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range concentrated {
+		if float64(row.SellerBids) < sellerConcentration*float64(row.TotalBids) {
+			continue
+		}
+
+		score := min(100, 40+10*row.SellerBids)
+		details := fmt.Sprintf("account younger than %d days placed %d of its %d bids on %d auctions of this seller", newAccountDays, row.SellerBids, row.TotalBids, row.SellerAuctions)
+		if err := ss.flag(ctx, row.BidderID, row.SellerID, ShillRuleNewAccountSingleSeller, score, details); err != nil {
+			return err
+		}
+	}
+
+	losers, err := ss.queries.ListNeverWinningBidders(ctx, neverWinsMinAuctions)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range losers {
+		score := min(100, 30+5*row.Auctions)
+		details := fmt.Sprintf("bid on %d ended auctions of this seller and never won", row.Auctions)
+		if err := ss.flag(ctx, row.BidderID, row.SellerID, ShillRuleNeverWins, score, details); err != nil {
+			return err
+		}
+	}
+
+	matches, err := ss.queries.ListSellerOriginMatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range matches {
+		var score int32
+		if row.SameIpBids > 0 {
+			score = 60
+		}
+		if row.SameDeviceBids > 0 {
+			score = 90
+		}
+
+		details := fmt.Sprintf("%d bids from an address and %d bids from a device used by the seller", row.SameIpBids, row.SameDeviceBids)
+		if err := ss.flag(ctx, row.BidderID, row.SellerID, ShillRuleSellerOrigin, score, details); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ss *ShillService) flag(ctx context.Context, bidderId, sellerId uuid.UUID, rule string, score int32, details string) error {
+	args := pgstore.UpsertShillFlagParams{
+		BidderID: bidderId,
+		SellerID: sellerId,
+		Rule:     rule,
+		Score:    score,
+		Details:  details,
+	}
+
+	return ss.queries.UpsertShillFlag(ctx, args)
+}
+
+// Run analyzes the bids every interval until ctx is cancelled.
+func (ss *ShillService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := ss.Analyze(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("failed to analyze bids for shill bidding", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ss *ShillService) ListFlags(ctx context.Context, status string) ([]pgstore.ShillFlag, error) {
+	return ss.queries.ListShillFlagsByStatus(ctx, status)
+}
+
+// ReviewFlag confirms or dismisses a pending flag.
+func (ss *ShillService) ReviewFlag(ctx context.Context, flagId, reviewerId uuid.UUID, confirm bool) (pgstore.ShillFlag, error) {
+	status := ShillFlagDismissed
+	if confirm {
+		status = ShillFlagConfirmed
+	}
+
+	args := pgstore.ReviewShillFlagParams{
+		ID:         flagId,
+		Status:     status,
+		ReviewerID: reviewerId,
+	}
+
+	flag, err := ss.queries.ReviewShillFlag(ctx, args)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.ShillFlag{}, err
+		}
+
+		if _, err := ss.queries.GetShillFlagById(ctx, flagId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.ShillFlag{}, ErrShillFlagNotFound
+			}
+
+			return pgstore.ShillFlag{}, err
+		}
+
+		return pgstore.ShillFlag{}, ErrShillFlagAlreadyReviewed
+	}
+
+	return flag, nil
+}
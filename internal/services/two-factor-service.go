@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/gregoryAlvim/gobid/internal/totp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled = errors.New("start the two-factor enrollment first")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrTwoFactorLocked      = errors.New("too many invalid two-factor codes, try again later")
+)
+
+// After maxTwoFactorFailures invalid codes in a row the second factor of the
+// user refuses every code for twoFactorLockout. The count is kept with the
+// secret, so logging in again does not reset it.
+const (
+	totpIssuer           = "gobid"
+	recoveryCodeCount    = 10
+	maxTwoFactorFailures = 5
+	twoFactorLockout     = 15 * time.Minute
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewTwoFactorService(pool *pgxpool.Pool) TwoFactorService {
+	return TwoFactorService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// TwoFactorEnrollment is what the user needs to add gobid to an
+// authenticator app, either by scanning the URI as a QR code or by typing
+// the secret.
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool  `json:"enabled"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+func (tfs *TwoFactorService) IsEnabled(ctx context.Context, userId uuid.UUID) (bool, error) {
+	userTotp, err := tfs.queries.GetUserTotp(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return userTotp.EnabledAt.Valid, nil
+}
+
+func (tfs *TwoFactorService) Status(ctx context.Context, userId uuid.UUID) (TwoFactorStatus, error) {
+	enabled, err := tfs.IsEnabled(ctx, userId)
+	if err != nil || !enabled {
+		return TwoFactorStatus{}, err
+	}
+
+	left, err := tfs.queries.CountUnusedRecoveryCodes(ctx, userId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	return TwoFactorStatus{Enabled: true, RecoveryCodesLeft: left}, nil
+}
+
+// Enroll starts two-factor enrollment with a new secret, replacing the one
+// of an enrollment that was never confirmed.
+func (tfs *TwoFactorService) Enroll(ctx context.Context, userId uuid.UUID) (TwoFactorEnrollment, error) {
+	user, err := tfs.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TwoFactorEnrollment{}, ErrUserNotFound
+		}
+
+		return TwoFactorEnrollment{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	args := pgstore.StartUserTotpEnrollmentParams{
+		UserID: userId,
+		Secret: secret,
+	}
+
+	started, err := tfs.queries.StartUserTotpEnrollment(ctx, args)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	if started == 0 {
+		return TwoFactorEnrollment{}, ErrTwoFactorEnabled
+	}
+
+	return TwoFactorEnrollment{
+		Secret:          totp.EncodeSecret(secret),
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user proves their
+// authenticator works by sending its current code. It returns the recovery
+// codes, which are not stored in the clear and cannot be shown again.
+func (tfs *TwoFactorService) Confirm(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	tx, err := tfs.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := tfs.queries.WithTx(tx)
+
+	userTotp, err := qtx.GetUserTotp(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+
+		return nil, err
+	}
+
+	if userTotp.EnabledAt.Valid {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	step, ok := totp.Validate(userTotp.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	args := pgstore.EnableUserTotpParams{
+		UserID:   userId,
+		LastStep: pgtype.Int8{Int64: step, Valid: true},
+	}
+
+	if _, err := qtx.EnableUserTotp(ctx, args); err != nil {
+		return nil, err
+	}
+
+	codes, err := replaceRecoveryCodes(ctx, qtx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Verify checks the second factor of userId: a code from the authenticator,
+// which is refused if it was already accepted, or one of the recovery codes,
+// which is used up. Invalid codes count towards the lockout, and the one
+// that reaches it returns ErrTwoFactorLocked.
+func (tfs *TwoFactorService) Verify(ctx context.Context, userId uuid.UUID, code string) error {
+	err := verifyTwoFactor(ctx, tfs.queries, userId, code)
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		return tfs.recordFailure(ctx, userId)
+	}
+
+	return err
+}
+
+// recordFailure counts an invalid code of userId. It runs outside of any
+// transaction of the caller, so the count survives its rollback.
+func (tfs *TwoFactorService) recordFailure(ctx context.Context, userId uuid.UUID) error {
+	args := pgstore.RecordTwoFactorFailureParams{
+		MaxFailures: maxTwoFactorFailures,
+		LockedUntil: time.Now().Add(twoFactorLockout),
+		UserID:      userId,
+	}
+
+	lockedUntil, err := tfs.queries.RecordTwoFactorFailure(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if lockedUntil.Valid && time.Now().Before(lockedUntil.Time) {
+		return ErrTwoFactorLocked
+	}
+
+	return ErrInvalidTwoFactorCode
+}
+
+// Disable turns two-factor authentication off after checking a code, and
+// throws away the secret and the recovery codes.
+func (tfs *TwoFactorService) Disable(ctx context.Context, userId uuid.UUID, code string) error {
+	tx, err := tfs.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := tfs.queries.WithTx(tx)
+
+	if err := verifyTwoFactor(ctx, qtx, userId, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			return tfs.recordFailure(ctx, userId)
+		}
+
+		return err
+	}
+
+	if err := qtx.DeleteUserTotp(ctx, userId); err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteRecoveryCodes(ctx, userId); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func verifyTwoFactor(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, code string) error {
+	userTotp, err := q.GetUserTotp(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTwoFactorNotEnabled
+		}
+
+		return err
+	}
+
+	if !userTotp.EnabledAt.Valid {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if userTotp.LockedUntil.Valid && time.Now().Before(userTotp.LockedUntil.Time) {
+		return ErrTwoFactorLocked
+	}
+
+	code = strings.TrimSpace(code)
+
+	if step, ok := totp.Validate(userTotp.Secret, code, time.Now()); ok {
+		args := pgstore.UseUserTotpStepParams{
+			UserID:   userId,
+			LastStep: pgtype.Int8{Int64: step, Valid: true},
+		}
+
+		used, err := q.UseUserTotpStep(ctx, args)
+		if err != nil {
+			return err
+		}
+
+		if used == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+
+		return q.ResetTwoFactorFailures(ctx, userId)
+	}
+
+	args := pgstore.UseRecoveryCodeParams{
+		UserID:   userId,
+		CodeHash: hashRecoveryCode(code),
+	}
+
+	used, err := q.UseRecoveryCode(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if used == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return q.ResetTwoFactorFailures(ctx, userId)
+}
+
+// replaceRecoveryCodes drops the recovery codes of userId and stores the
+// hashes of new ones, which are returned formatted as xxxx-xxxx-xxxx-xxxx.
+func replaceRecoveryCodes(ctx context.Context, q *pgstore.Queries, userId uuid.UUID) ([]string, error) {
+	if err := q.DeleteRecoveryCodes(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))
+		codes[i] = encoded[0:4] + "-" + encoded[4:8] + "-" + encoded[8:12] + "-" + encoded[12:16]
+
+		args := pgstore.CreateRecoveryCodeParams{
+			UserID:   userId,
+			CodeHash: hashRecoveryCode(codes[i]),
+		}
+
+		if err := q.CreateRecoveryCode(ctx, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
+
+// hashRecoveryCode hashes code ignoring case, spaces and dashes, so the
+// user may type it however it is easiest. The codes carry 80 random bits,
+// which makes a plain SHA-256 enough.
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(code))
+
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
@@ -15,6 +16,8 @@ import (
 var (
 	ErrDuplicatedEmailOrUsername = errors.New("username or email already exists")
 	ErrInvalidCredentials        = errors.New("invalid credentials")
+	ErrUserNotFound              = errors.New("user not found")
+	ErrNoProfileChanges          = errors.New("no profile changes")
 )
 
 type UserService struct {
@@ -72,3 +75,262 @@ func (us *UserService) AuthenticateUser(ctx context.Context, email, password str
 
 	return user.ID, nil
 }
+
+// FindUserIdByEmail returns the id of the user registered with email.
+func (us *UserService) FindUserIdByEmail(ctx context.Context, email string) (uuid.UUID, error) {
+	user, err := us.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrUserNotFound
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	return user.ID, nil
+}
+
+// RecordOrigin remembers an address and device the user has been seen on.
+func (us *UserService) RecordOrigin(ctx context.Context, userId uuid.UUID, origin RequestOrigin) error {
+	if !origin.IP.IsValid() {
+		return nil
+	}
+
+	args := pgstore.RecordUserOriginParams{
+		UserID:    userId,
+		IpAddress: origin.IP,
+		DeviceID:  origin.DeviceID,
+	}
+
+	return us.queries.RecordUserOrigin(ctx, args)
+}
+
+// MarkEmailVerified verifies the email of userId without a token, for tools
+// that create users on their own.
+func (us *UserService) MarkEmailVerified(ctx context.Context, userId uuid.UUID) error {
+	return us.queries.MarkUserEmailVerified(ctx, userId)
+}
+
+func (us *UserService) IsAdmin(ctx context.Context, userId uuid.UUID) (bool, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return user.IsAdmin, nil
+}
+
+type SellerStats struct {
+	Listed    int32 `json:"listed"`
+	Live      int32 `json:"live"`
+	Sold      int32 `json:"sold"`
+	Unsold    int32 `json:"unsold"`
+	UnitsSold int32 `json:"units_sold"`
+}
+
+// PublicProfile is what anyone can see about a user.
+type PublicProfile struct {
+	ID              uuid.UUID   `json:"id"`
+	UserName        string      `json:"user_name"`
+	Bio             string      `json:"bio"`
+	MemberSince     time.Time   `json:"member_since"`
+	SellerStats     SellerStats `json:"seller_stats"`
+	RetractionCount int32       `json:"retraction_count"`
+}
+
+// Profile is the public profile plus the fields only its owner sees.
+type Profile struct {
+	PublicProfile
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	IsAdmin       bool      `json:"is_admin"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ProfileChanges holds the fields of a profile update; nil fields are kept.
+type ProfileChanges struct {
+	UserName *string
+	Bio      *string
+}
+
+func (us *UserService) GetPublicProfile(ctx context.Context, userId uuid.UUID) (PublicProfile, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PublicProfile{}, ErrUserNotFound
+		}
+
+		return PublicProfile{}, err
+	}
+
+	return us.publicProfile(ctx, user)
+}
+
+func (us *UserService) GetProfile(ctx context.Context, userId uuid.UUID) (Profile, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Profile{}, ErrUserNotFound
+		}
+
+		return Profile{}, err
+	}
+
+	return us.profile(ctx, user)
+}
+
+func (us *UserService) UpdateProfile(ctx context.Context, userId uuid.UUID, changes ProfileChanges) (Profile, error) {
+	if changes.UserName == nil && changes.Bio == nil {
+		return Profile{}, ErrNoProfileChanges
+	}
+
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Profile{}, ErrUserNotFound
+		}
+
+		return Profile{}, err
+	}
+
+	args := pgstore.UpdateUserProfileParams{
+		ID:       userId,
+		UserName: user.UserName,
+		Bio:      user.Bio,
+	}
+
+	if changes.UserName != nil {
+		args.UserName = *changes.UserName
+	}
+
+	if changes.Bio != nil {
+		args.Bio = *changes.Bio
+	}
+
+	user, err = us.queries.UpdateUserProfile(ctx, args)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return Profile{}, ErrDuplicatedEmailOrUsername
+		}
+
+		return Profile{}, err
+	}
+
+	return us.profile(ctx, user)
+}
+
+// ChangePassword replaces the password of userId after checking the current
+// one. Like a reset, it revokes the personal access tokens of the user and
+// ends all of their sessions by moving on to a new session generation,
+// which it returns so the caller can keep the current session alive.
+func (us *UserService) ChangePassword(ctx context.Context, userId uuid.UUID, currentPassword, newPassword string) (int32, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+
+		return 0, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(currentPassword)); err != nil {
+		return 0, ErrInvalidCredentials
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := us.queries.WithTx(tx)
+
+	if err := us.setPassword(ctx, qtx, userId, newPassword); err != nil {
+		return 0, err
+	}
+
+	if err := qtx.DeleteUserAccessTokens(ctx, userId); err != nil {
+		return 0, err
+	}
+
+	generation, err := qtx.BumpUserSessionGeneration(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return generation, nil
+}
+
+// SessionGeneration returns the current session generation of userId.
+// Sessions logged in under an older generation are no longer valid.
+func (us *UserService) SessionGeneration(ctx context.Context, userId uuid.UUID) (int32, error) {
+	generation, err := us.queries.GetUserSessionGeneration(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+
+		return 0, err
+	}
+
+	return generation, nil
+}
+
+// setPassword stores the bcrypt hash of password for userId using q, so
+// that callers can make it part of a transaction.
+func (us *UserService) setPassword(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	if err != nil {
+		return err
+	}
+
+	args := pgstore.UpdateUserPasswordParams{
+		ID:           userId,
+		PasswordHash: hash,
+	}
+
+	return q.UpdateUserPassword(ctx, args)
+}
+
+func (us *UserService) publicProfile(ctx context.Context, user pgstore.User) (PublicProfile, error) {
+	stats, err := us.queries.GetSellerStats(ctx, user.ID)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+
+	return PublicProfile{
+		ID:          user.ID,
+		UserName:    user.UserName,
+		Bio:         user.Bio,
+		MemberSince: user.CreatedAt,
+		SellerStats: SellerStats{
+			Listed:    stats.ListedCount,
+			Live:      stats.LiveCount,
+			Sold:      stats.SoldCount,
+			Unsold:    stats.UnsoldCount,
+			UnitsSold: stats.UnitsSold,
+		},
+		RetractionCount: user.RetractionCount,
+	}, nil
+}
+
+func (us *UserService) profile(ctx context.Context, user pgstore.User) (Profile, error) {
+	public, err := us.publicProfile(ctx, user)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	return Profile{
+		PublicProfile: public,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		IsAdmin:       user.IsAdmin,
+		UpdatedAt:     user.UpdatedAt,
+	}, nil
+}
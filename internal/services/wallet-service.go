@@ -0,0 +1,292 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrInsufficientFunds = errors.New("insufficient funds in your wallet for this bid")
+
+// ErrCodeInsufficientFunds is sent to clients together with
+// ErrInsufficientFunds so they can tell it apart from other rejections.
+const ErrCodeInsufficientFunds = "insufficient_funds"
+
+const (
+	WalletDeposit    = "deposit"
+	WalletWithdrawal = "withdrawal"
+)
+
+const walletHistorySize = 50
+
+type WalletService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewWalletService(pool *pgxpool.Pool) WalletService {
+	return WalletService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// Balance is a wallet in one currency. Available is what can still be bid
+// or withdrawn.
+type Balance struct {
+	Balance   money.Money `json:"balance"`
+	Held      money.Money `json:"held"`
+	Available money.Money `json:"available"`
+}
+
+type WalletTransaction struct {
+	ID        uuid.UUID   `json:"id"`
+	Kind      string      `json:"kind"`
+	Amount    money.Money `json:"amount"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Wallet struct {
+	Balances     []Balance           `json:"balances"`
+	Transactions []WalletTransaction `json:"transactions"`
+}
+
+func (ws *WalletService) GetWallet(ctx context.Context, userId uuid.UUID) (Wallet, error) {
+	wallets, err := ws.queries.ListWalletsByUserId(ctx, userId)
+	if err != nil {
+		return Wallet{}, err
+	}
+
+	transactions, err := ws.queries.ListWalletTransactionsByUserId(ctx, pgstore.ListWalletTransactionsByUserIdParams{
+		UserID: userId,
+		Limit:  walletHistorySize,
+	})
+	if err != nil {
+		return Wallet{}, err
+	}
+
+	wallet := Wallet{
+		Balances:     make([]Balance, 0, len(wallets)),
+		Transactions: make([]WalletTransaction, 0, len(transactions)),
+	}
+
+	for _, w := range wallets {
+		wallet.Balances = append(wallet.Balances, Balance{
+			Balance:   money.New(w.Balance, w.Currency),
+			Held:      money.New(w.Held, w.Currency),
+			Available: money.New(w.Balance-w.Held, w.Currency),
+		})
+	}
+
+	for _, t := range transactions {
+		wallet.Transactions = append(wallet.Transactions, WalletTransaction{
+			ID:        t.ID,
+			Kind:      t.Kind,
+			Amount:    money.New(t.Amount, t.Currency),
+			CreatedAt: t.CreatedAt,
+		})
+	}
+
+	return wallet, nil
+}
+
+func (ws *WalletService) Deposit(ctx context.Context, userId uuid.UUID, amount money.Money) (pgstore.WalletTransaction, error) {
+	tx, err := ws.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.WalletTransaction{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ws.queries.WithTx(tx)
+
+	if _, err := qtx.CreditWallet(ctx, pgstore.CreditWalletParams{
+		UserID:   userId,
+		Currency: amount.Currency,
+		Balance:  amount.Amount,
+	}); err != nil {
+		return pgstore.WalletTransaction{}, err
+	}
+
+	return ws.record(ctx, tx, qtx, userId, WalletDeposit, amount)
+}
+
+// Withdraw takes money out of the wallet. Funds held by active bids cannot be
+// withdrawn.
+func (ws *WalletService) Withdraw(ctx context.Context, userId uuid.UUID, amount money.Money) (pgstore.WalletTransaction, error) {
+	tx, err := ws.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.WalletTransaction{}, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := ws.queries.WithTx(tx)
+
+	if _, err := qtx.DebitWallet(ctx, pgstore.DebitWalletParams{
+		UserID:   userId,
+		Currency: amount.Currency,
+		Balance:  amount.Amount,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.WalletTransaction{}, ErrInsufficientFunds
+		}
+
+		return pgstore.WalletTransaction{}, err
+	}
+
+	return ws.record(ctx, tx, qtx, userId, WalletWithdrawal, amount)
+}
+
+func (ws *WalletService) record(ctx context.Context, tx pgx.Tx, qtx *pgstore.Queries, userId uuid.UUID, kind string, amount money.Money) (pgstore.WalletTransaction, error) {
+	transaction, err := qtx.CreateWalletTransaction(ctx, pgstore.CreateWalletTransactionParams{
+		UserID:   userId,
+		Currency: amount.Currency,
+		Kind:     kind,
+		Amount:   amount.Amount,
+	})
+	if err != nil {
+		return pgstore.WalletTransaction{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.WalletTransaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// holdAmount is the part of a bid that must be covered by the wallet:
+// percent of the amount of every unit the bid asks for.
+func holdAmount(bid pgstore.Bid, currency string, percent int64) (int64, error) {
+	total, err := money.New(bid.BidAmount, currency).Mul(int64(bid.Quantity))
+	if err != nil {
+		return 0, err
+	}
+
+	held, err := total.PercentCeil(percent)
+	if err != nil {
+		return 0, err
+	}
+
+	return held.Amount, nil
+}
+
+// holdFunds commits the bidder's funds to a new bid and frees the funds of
+// the bidders it outbid, if any. The bidder's previous hold on the auction
+// is replaced, so only the difference is taken from the wallet; the hold
+// covers every unit the bid asks for. Wallet rows are always updated in user
+// id order, so concurrent bids on different auctions cannot deadlock on each
+// other's wallets.
+func holdFunds(ctx context.Context, qtx *pgstore.Queries, bid pgstore.Bid, currency string, percent int64, outbidIds []uuid.UUID) error {
+	previous, err := qtx.GetActiveBidHold(ctx, pgstore.GetActiveBidHoldParams{UserID: bid.BidderID, ProductID: bid.ProductID})
+	hasPrevious := err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	var outbid []pgstore.BidHold
+	for _, outbidId := range outbidIds {
+		if outbidId == bid.BidderID {
+			continue
+		}
+
+		hold, err := qtx.GetActiveBidHold(ctx, pgstore.GetActiveBidHoldParams{UserID: outbidId, ProductID: bid.ProductID})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if err == nil {
+			outbid = append(outbid, hold)
+		}
+	}
+
+	amount, err := holdAmount(bid, currency, percent)
+	if err != nil {
+		return err
+	}
+
+	hold := func() error {
+		delta := amount
+		if hasPrevious {
+			delta -= previous.Amount
+
+			if _, err := qtx.ReleaseBidHold(ctx, previous.ID); err != nil {
+				return err
+			}
+		}
+
+		if delta > 0 {
+			if _, err := qtx.HoldWalletFunds(ctx, pgstore.HoldWalletFundsParams{UserID: bid.BidderID, Currency: currency, Held: delta}); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrInsufficientFunds
+				}
+
+				return err
+			}
+		} else if delta < 0 {
+			if err := qtx.ReleaseWalletFunds(ctx, pgstore.ReleaseWalletFundsParams{UserID: bid.BidderID, Currency: currency, Held: -delta}); err != nil {
+				return err
+			}
+		}
+
+		_, err := qtx.CreateBidHold(ctx, pgstore.CreateBidHoldParams{
+			UserID:    bid.BidderID,
+			ProductID: bid.ProductID,
+			BidID:     bid.ID,
+			Amount:    amount,
+			Currency:  currency,
+		})
+		return err
+	}
+
+	slices.SortFunc(outbid, func(a, b pgstore.BidHold) int {
+		return bytes.Compare(a.UserID[:], b.UserID[:])
+	})
+
+	held := false
+	for _, outbidHold := range outbid {
+		if !held && bytes.Compare(bid.BidderID[:], outbidHold.UserID[:]) < 0 {
+			held = true
+			if err := hold(); err != nil {
+				return err
+			}
+		}
+
+		if err := releaseHold(ctx, qtx, outbidHold); err != nil {
+			return err
+		}
+	}
+
+	if held {
+		return nil
+	}
+
+	return hold()
+}
+
+// releaseHold gives the funds of an active hold back to the wallet.
+func releaseHold(ctx context.Context, qtx *pgstore.Queries, hold pgstore.BidHold) error {
+	released, err := qtx.ReleaseBidHold(ctx, hold.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	return qtx.ReleaseWalletFunds(ctx, pgstore.ReleaseWalletFundsParams{
+		UserID:   released.UserID,
+		Currency: released.Currency,
+		Held:     released.Amount,
+	})
+}
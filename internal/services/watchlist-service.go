@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/notify"
+	"github.com/gregoryAlvim/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrNotWatching = errors.New("this product is not on your watchlist")
+
+const (
+	endingSoonWindow  = 15 * time.Minute
+	reminderBatchSize = 100
+)
+
+type WatchlistService struct {
+	pool     *pgxpool.Pool
+	queries  *pgstore.Queries
+	notifier notify.Notifier
+}
+
+func NewWatchlistService(pool *pgxpool.Pool, notifier notify.Notifier) WatchlistService {
+	return WatchlistService{
+		pool:     pool,
+		queries:  pgstore.New(pool),
+		notifier: notifier,
+	}
+}
+
+// WatchProduct adds the product to the user's watchlist. Watching a product
+// twice is not an error, but drafts and auctions that are over cannot be
+// watched.
+func (ws *WatchlistService) WatchProduct(ctx context.Context, userId, productId uuid.UUID) error {
+	product, err := ws.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFound
+		}
+
+		return err
+	}
+
+	if !product.PublishedAt.Valid {
+		return ErrProductNotFound
+	}
+
+	if !time.Now().Before(product.AuctionEnd) {
+		return ErrAuctionClosed
+	}
+
+	return ws.queries.AddToWatchlist(ctx, pgstore.AddToWatchlistParams{UserID: userId, ProductID: productId})
+}
+
+func (ws *WatchlistService) UnwatchProduct(ctx context.Context, userId, productId uuid.UUID) error {
+	removed, err := ws.queries.RemoveFromWatchlist(ctx, pgstore.RemoveFromWatchlistParams{UserID: userId, ProductID: productId})
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return ErrNotWatching
+	}
+
+	return nil
+}
+
+// ListWatchlist returns the products the user watches, ending soonest first.
+func (ws *WatchlistService) ListWatchlist(ctx context.Context, userId uuid.UUID) ([]ProductDetails, error) {
+	rows, err := ws.queries.ListWatchedProducts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]ProductDetails, 0, len(rows))
+	for _, row := range rows {
+		products = append(products, newProductDetails(pgstore.GetProductWithStatsByIdRow(row)))
+	}
+
+	if err := loadCollections(ctx, ws.queries, products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// SendReminders tells watchers about auctions ending within the next 15
+// minutes and about finalized auctions. Each reminder is marked as sent
+// before it goes out, so a failing notifier never makes a user get the same
+// reminder twice.
+func (ws *WatchlistService) SendReminders(ctx context.Context) error {
+	for {
+		rows, err := ws.queries.ListDueEndingSoonWatches(ctx, pgstore.ListDueEndingSoonWatchesParams{
+			EndingBefore: time.Now().Add(endingSoonWindow),
+			Limit:        reminderBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := ws.queries.MarkWatchEndingSoonSent(ctx, pgstore.MarkWatchEndingSoonSentParams{UserID: row.UserID, ProductID: row.ProductID}); err != nil {
+				return err
+			}
+
+			ws.notify(ctx, notify.Notification{
+				UserID:    row.UserID,
+				Email:     row.Email,
+				UserName:  row.UserName,
+				ProductID: row.ProductID,
+				Kind:      notify.KindAuctionEndingSoon,
+				Subject:   fmt.Sprintf("%q is ending soon", row.ProductName),
+				Body:      fmt.Sprintf("The auction for %q ends in %d minutes, at %s.", row.ProductName, max(int(time.Until(row.AuctionEnd).Minutes()), 1), row.AuctionEnd.UTC().Format(time.RFC1123)),
+			})
+		}
+
+		if len(rows) < reminderBatchSize {
+			break
+		}
+	}
+
+	for {
+		rows, err := ws.queries.ListDueEndedWatches(ctx, reminderBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := ws.queries.MarkWatchEndedSent(ctx, pgstore.MarkWatchEndedSentParams{UserID: row.UserID, ProductID: row.ProductID}); err != nil {
+				return err
+			}
+
+			body := fmt.Sprintf("The auction for %q has ended without a winner.", row.ProductName)
+			if row.IsSold {
+				body = fmt.Sprintf("The auction for %q has ended and the item was sold.", row.ProductName)
+			}
+
+			ws.notify(ctx, notify.Notification{
+				UserID:    row.UserID,
+				Email:     row.Email,
+				UserName:  row.UserName,
+				ProductID: row.ProductID,
+				Kind:      notify.KindAuctionEnded,
+				Subject:   fmt.Sprintf("%q has ended", row.ProductName),
+				Body:      body,
+			})
+		}
+
+		if len(rows) < reminderBatchSize {
+			return nil
+		}
+	}
+}
+
+func (ws *WatchlistService) notify(ctx context.Context, n notify.Notification) {
+	if err := ws.notifier.Notify(ctx, n); err != nil {
+		slog.Error("failed to send notification", "user_id", n.UserID, "product_id", n.ProductID, "kind", n.Kind, "error", err)
+	}
+}
+
+// RunReminders sends the due watchlist reminders every interval until ctx
+// is cancelled.
+func (ws *WatchlistService) RunReminders(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := ws.SendReminders(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("failed to send watchlist reminders", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
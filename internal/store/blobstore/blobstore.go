@@ -0,0 +1,22 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps binary objects, such as product images, under slash
+// separated keys like "products/<id>/<image>".
+type BlobStore interface {
+	// Put stores the content read from r under key, replacing any previous
+	// blob with the same key.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns the content stored under key, or ErrBlobNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
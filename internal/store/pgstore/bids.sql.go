@@ -7,24 +7,59 @@ package pgstore
 
 import (
 	"context"
+	"net/netip"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countBidAuctionsByBidderId = `-- name: CountBidAuctionsByBidderId :one
+SELECT COUNT(DISTINCT product_id) FROM bids WHERE bidder_id = $1 AND voided_at IS NULL
+`
+
+func (q *Queries) CountBidAuctionsByBidderId(ctx context.Context, bidderID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBidAuctionsByBidderId, bidderID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBidsByProductId = `-- name: CountBidsByProductId :one
+SELECT COUNT(*) FROM bids WHERE product_id = $1
+`
+
+func (q *Queries) CountBidsByProductId(ctx context.Context, productID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBidsByProductId, productID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBid = `-- name: CreateBid :one
-INSERT INTO bids ("product_id", "bidder_id", "bid_amount")
-VALUES ($1, $2, $3) 
-RETURNING id, product_id, bidder_id, bid_amount, created_at
+INSERT INTO bids ("product_id", "bidder_id", "bid_amount", "ip_address", "device_id", "quantity")
+VALUES ($1, $2, $3, $4, $5, $6) 
+RETURNING id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity
 `
 
 type CreateBidParams struct {
-	ProductID uuid.UUID `json:"product_id"`
-	BidderID  uuid.UUID `json:"bidder_id"`
-	BidAmount float64   `json:"bid_amount"`
+	ProductID uuid.UUID   `json:"product_id"`
+	BidderID  uuid.UUID   `json:"bidder_id"`
+	BidAmount int64       `json:"bid_amount"`
+	IpAddress *netip.Addr `json:"ip_address"`
+	DeviceID  pgtype.Text `json:"device_id"`
+	Quantity  int32       `json:"quantity"`
 }
 
 func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) (Bid, error) {
-	row := q.db.QueryRow(ctx, createBid, arg.ProductID, arg.BidderID, arg.BidAmount)
+	row := q.db.QueryRow(ctx, createBid,
+		arg.ProductID,
+		arg.BidderID,
+		arg.BidAmount,
+		arg.IpAddress,
+		arg.DeviceID,
+		arg.Quantity,
+	)
 	var i Bid
 	err := row.Scan(
 		&i.ID,
@@ -32,12 +67,37 @@ func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) (Bid, erro
 		&i.BidderID,
 		&i.BidAmount,
 		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.IpAddress,
+		&i.DeviceID,
+		&i.Quantity,
+	)
+	return i, err
+}
+
+const getBidById = `-- name: GetBidById :one
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity FROM bids WHERE id = $1
+`
+
+func (q *Queries) GetBidById(ctx context.Context, id uuid.UUID) (Bid, error) {
+	row := q.db.QueryRow(ctx, getBidById, id)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.BidAmount,
+		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.IpAddress,
+		&i.DeviceID,
+		&i.Quantity,
 	)
 	return i, err
 }
 
 const getBidsByProductId = `-- name: GetBidsByProductId :many
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids WHERE product_id = $1 ORDER BY bid_amount DESC
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity FROM bids WHERE product_id = $1 ORDER BY bid_amount DESC
 `
 
 func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) ([]Bid, error) {
@@ -55,6 +115,10 @@ func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) (
 			&i.BidderID,
 			&i.BidAmount,
 			&i.CreatedAt,
+			&i.VoidedAt,
+			&i.IpAddress,
+			&i.DeviceID,
+			&i.Quantity,
 		); err != nil {
 			return nil, err
 		}
@@ -67,7 +131,7 @@ func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) (
 }
 
 const getHighestBidByProductId = `-- name: GetHighestBidByProductId :one
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids WHERE product_id = $1 ORDER BY bid_amount DESC LIMIT 1
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity FROM bids WHERE product_id = $1 AND voided_at IS NULL ORDER BY bid_amount DESC LIMIT 1
 `
 
 func (q *Queries) GetHighestBidByProductId(ctx context.Context, productID uuid.UUID) (Bid, error) {
@@ -79,6 +143,252 @@ func (q *Queries) GetHighestBidByProductId(ctx context.Context, productID uuid.U
 		&i.BidderID,
 		&i.BidAmount,
 		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.IpAddress,
+		&i.DeviceID,
+		&i.Quantity,
+	)
+	return i, err
+}
+
+const listBidSummariesByBidderId = `-- name: ListBidSummariesByBidderId :many
+SELECT products.id AS product_id, products.seller_id, products.product_name, products.currency, products.starts_at, products.auction_end,
+       products.base_price, products.quantity, products.pricing, products.finalized_at,
+       mine.my_highest_bid::bigint AS my_highest_bid,
+       mine.last_bid_at::timestamptz AS last_bid_at,
+       EXISTS (
+         SELECT 1 FROM auction_results
+         WHERE auction_results.product_id = products.id AND auction_results.bidder_id = $1
+       ) AS won
+FROM (
+  SELECT product_id, MAX(bid_amount) AS my_highest_bid, MAX(created_at) AS last_bid_at
+  FROM bids
+  WHERE bidder_id = $1 AND voided_at IS NULL
+  GROUP BY product_id
+) mine
+JOIN products ON products.id = mine.product_id
+ORDER BY mine.last_bid_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListBidSummariesByBidderIdParams struct {
+	BidderID uuid.UUID `json:"bidder_id"`
+	Limit    int32     `json:"limit"`
+	Offset   int32     `json:"offset"`
+}
+
+type ListBidSummariesByBidderIdRow struct {
+	ProductID    uuid.UUID          `json:"product_id"`
+	SellerID     uuid.UUID          `json:"seller_id"`
+	ProductName  string             `json:"product_name"`
+	Currency     string             `json:"currency"`
+	StartsAt     time.Time          `json:"starts_at"`
+	AuctionEnd   time.Time          `json:"auction_end"`
+	BasePrice    int64              `json:"base_price"`
+	Quantity     int32              `json:"quantity"`
+	Pricing      string             `json:"pricing"`
+	FinalizedAt  pgtype.Timestamptz `json:"finalized_at"`
+	MyHighestBid int64              `json:"my_highest_bid"`
+	LastBidAt    time.Time          `json:"last_bid_at"`
+	Won          bool               `json:"won"`
+}
+
+func (q *Queries) ListBidSummariesByBidderId(ctx context.Context, arg ListBidSummariesByBidderIdParams) ([]ListBidSummariesByBidderIdRow, error) {
+	rows, err := q.db.Query(ctx, listBidSummariesByBidderId, arg.BidderID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBidSummariesByBidderIdRow
+	for rows.Next() {
+		var i ListBidSummariesByBidderIdRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Currency,
+			&i.StartsAt,
+			&i.AuctionEnd,
+			&i.BasePrice,
+			&i.Quantity,
+			&i.Pricing,
+			&i.FinalizedAt,
+			&i.MyHighestBid,
+			&i.LastBidAt,
+			&i.Won,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBidsByProductId = `-- name: ListBidsByProductId :many
+SELECT bids.id, bids.product_id, bids.bidder_id, bids.bid_amount, bids.created_at, bids.voided_at, bids.quantity, users.user_name AS bidder_name, bidder_pseudonyms.number AS bidder_number
+FROM bids
+JOIN users ON users.id = bids.bidder_id
+JOIN bidder_pseudonyms ON bidder_pseudonyms.product_id = bids.product_id AND bidder_pseudonyms.user_id = bids.bidder_id
+WHERE bids.product_id = $1
+  AND ($2::timestamptz IS NULL OR bids.created_at >= $2)
+  AND ($3::timestamptz IS NULL OR bids.created_at < $3)
+  AND ($4::timestamptz IS NULL OR (bids.created_at, bids.id) < ($4, $5::uuid))
+ORDER BY bids.created_at DESC, bids.id DESC
+LIMIT $6
+`
+
+type ListBidsByProductIdParams struct {
+	ProductID       uuid.UUID          `json:"product_id"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+type ListBidsByProductIdRow struct {
+	ID           uuid.UUID          `json:"id"`
+	ProductID    uuid.UUID          `json:"product_id"`
+	BidderID     uuid.UUID          `json:"bidder_id"`
+	BidAmount    int64              `json:"bid_amount"`
+	CreatedAt    time.Time          `json:"created_at"`
+	VoidedAt     pgtype.Timestamptz `json:"voided_at"`
+	Quantity     int32              `json:"quantity"`
+	BidderName   string             `json:"bidder_name"`
+	BidderNumber int32              `json:"bidder_number"`
+}
+
+func (q *Queries) ListBidsByProductId(ctx context.Context, arg ListBidsByProductIdParams) ([]ListBidsByProductIdRow, error) {
+	rows, err := q.db.Query(ctx, listBidsByProductId,
+		arg.ProductID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBidsByProductIdRow
+	for rows.Next() {
+		var i ListBidsByProductIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.BidderID,
+			&i.BidAmount,
+			&i.CreatedAt,
+			&i.VoidedAt,
+			&i.Quantity,
+			&i.BidderName,
+			&i.BidderNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStandingBids = `-- name: ListStandingBids :many
+SELECT DISTINCT ON (bidder_id) id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity
+FROM bids
+WHERE product_id = $1 AND voided_at IS NULL
+ORDER BY bidder_id, created_at DESC
+`
+
+func (q *Queries) ListStandingBids(ctx context.Context, productID uuid.UUID) ([]Bid, error) {
+	rows, err := q.db.Query(ctx, listStandingBids, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bid
+	for rows.Next() {
+		var i Bid
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.BidderID,
+			&i.BidAmount,
+			&i.CreatedAt,
+			&i.VoidedAt,
+			&i.IpAddress,
+			&i.DeviceID,
+			&i.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStandingBidsByProductIds = `-- name: ListStandingBidsByProductIds :many
+SELECT DISTINCT ON (product_id, bidder_id) id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity
+FROM bids
+WHERE product_id = ANY($1::uuid[]) AND voided_at IS NULL
+ORDER BY product_id, bidder_id, created_at DESC
+`
+
+func (q *Queries) ListStandingBidsByProductIds(ctx context.Context, productIds []uuid.UUID) ([]Bid, error) {
+	rows, err := q.db.Query(ctx, listStandingBidsByProductIds, productIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bid
+	for rows.Next() {
+		var i Bid
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.BidderID,
+			&i.BidAmount,
+			&i.CreatedAt,
+			&i.VoidedAt,
+			&i.IpAddress,
+			&i.DeviceID,
+			&i.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const voidBid = `-- name: VoidBid :one
+UPDATE bids SET voided_at = now() WHERE id = $1 AND voided_at IS NULL RETURNING id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity
+`
+
+func (q *Queries) VoidBid(ctx context.Context, id uuid.UUID) (Bid, error) {
+	row := q.db.QueryRow(ctx, voidBid, id)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.BidAmount,
+		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.IpAddress,
+		&i.DeviceID,
+		&i.Quantity,
 	)
 	return i, err
 }
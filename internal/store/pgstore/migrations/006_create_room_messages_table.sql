@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS room_messages (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  sender_id UUID NOT NULL REFERENCES users (id),
+  content TEXT NOT NULL,
+  deleted_at TIMESTAMPTZ,
+  deleted_by UUID REFERENCES users (id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX room_messages_product_id_created_at_idx ON room_messages (product_id, created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS room_messages_product_id_created_at_idx;
+DROP TABLE IF EXISTS room_messages;
@@ -0,0 +1,5 @@
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ;
+
+---- create above / drop below ----
+
+ALTER TABLE bids DROP COLUMN IF EXISTS voided_at;
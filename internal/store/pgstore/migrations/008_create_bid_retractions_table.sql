@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS bid_retractions (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  bid_id UUID NOT NULL REFERENCES bids (id) ON DELETE CASCADE,
+  requester_id UUID NOT NULL REFERENCES users (id),
+  reason TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  reviewer_id UUID REFERENCES users (id),
+  reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX bid_retractions_pending_bid_id_idx ON bid_retractions (bid_id) WHERE status = 'pending';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS retraction_count INTEGER NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE users DROP COLUMN IF EXISTS retraction_count;
+DROP INDEX IF EXISTS bid_retractions_pending_bid_id_idx;
+DROP TABLE IF EXISTS bid_retractions;
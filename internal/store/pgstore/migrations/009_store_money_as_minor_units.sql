@@ -0,0 +1,13 @@
+-- Monetary values are stored as integer minor units (cents for BRL) next to
+-- the ISO 4217 code of the product currency. Existing rows were recorded in
+-- reais, so they are converted with two decimal places.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE products ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE products ALTER COLUMN base_price TYPE BIGINT USING round(base_price * 100)::BIGINT;
+ALTER TABLE bids ALTER COLUMN bid_amount TYPE BIGINT USING round(bid_amount * 100)::BIGINT;
+
+---- create above / drop below ----
+
+ALTER TABLE bids ALTER COLUMN bid_amount TYPE FLOAT USING bid_amount / 100.0;
+ALTER TABLE products ALTER COLUMN base_price TYPE FLOAT USING base_price / 100.0;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
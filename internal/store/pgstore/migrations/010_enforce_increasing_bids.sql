@@ -0,0 +1,34 @@
+-- Accepted bids for a product must be strictly increasing. The trigger locks
+-- the product row so concurrent inserts are serialized even when they do not
+-- go through BidsService, and created_at uses clock_timestamp() so the order
+-- of the rows matches the order in which they were accepted.
+ALTER TABLE bids ALTER COLUMN created_at SET DEFAULT clock_timestamp();
+
+CREATE OR REPLACE FUNCTION enforce_increasing_bids() RETURNS TRIGGER AS $$
+DECLARE
+  highest BIGINT;
+BEGIN
+  PERFORM 1 FROM products WHERE id = NEW.product_id FOR UPDATE;
+
+  SELECT max(bid_amount) INTO highest
+  FROM bids
+  WHERE product_id = NEW.product_id AND voided_at IS NULL;
+
+  IF highest IS NOT NULL AND NEW.bid_amount <= highest THEN
+    RAISE EXCEPTION 'bid amount % must be greater than the highest bid %', NEW.bid_amount, highest
+      USING ERRCODE = 'check_violation';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bids_strictly_increasing
+BEFORE INSERT ON bids
+FOR EACH ROW EXECUTE FUNCTION enforce_increasing_bids();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS bids_strictly_increasing ON bids;
+DROP FUNCTION IF EXISTS enforce_increasing_bids();
+ALTER TABLE bids ALTER COLUMN created_at SET DEFAULT now();
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  scope TEXT NOT NULL,
+  key TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  response JSONB NOT NULL DEFAULT 'null',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (user_id, scope, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idempotency_keys_expires_at_idx;
+DROP TABLE IF EXISTS idempotency_keys;
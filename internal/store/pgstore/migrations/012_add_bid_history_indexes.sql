@@ -0,0 +1,7 @@
+CREATE INDEX IF NOT EXISTS bids_product_id_created_at_idx ON bids (product_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS bids_bidder_id_idx ON bids (bidder_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS bids_bidder_id_idx;
+DROP INDEX IF EXISTS bids_product_id_created_at_idx;
@@ -0,0 +1,22 @@
+-- Every participant of an auction gets a stable number there ("Bidder 7"),
+-- assigned in the order they first took part in it.
+CREATE TABLE IF NOT EXISTS bidder_pseudonyms (
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  number INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (product_id, user_id),
+  UNIQUE (product_id, number)
+);
+
+INSERT INTO bidder_pseudonyms (product_id, user_id, number)
+SELECT product_id, bidder_id, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY first_bid_at, bidder_id)
+FROM (
+  SELECT product_id, bidder_id, MIN(created_at) AS first_bid_at
+  FROM bids
+  GROUP BY product_id, bidder_id
+) first_bids;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS bidder_pseudonyms;
@@ -0,0 +1,38 @@
+-- Where bids come from, so they can be compared with the places the seller
+-- uses. Older bids have no origin.
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS ip_address INET;
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS device_id TEXT;
+
+CREATE TABLE IF NOT EXISTS user_origins (
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  ip_address INET NOT NULL,
+  device_id TEXT NOT NULL DEFAULT '',
+  first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, ip_address, device_id)
+);
+
+CREATE TABLE IF NOT EXISTS shill_flags (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  bidder_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  seller_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  rule TEXT NOT NULL,
+  score INTEGER NOT NULL,
+  details TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed', 'confirmed')),
+  reviewer_id UUID REFERENCES users (id),
+  reviewed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (bidder_id, seller_id, rule)
+);
+
+CREATE INDEX shill_flags_status_idx ON shill_flags (status, score DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS shill_flags_status_idx;
+DROP TABLE IF EXISTS shill_flags;
+DROP TABLE IF EXISTS user_origins;
+ALTER TABLE bids DROP COLUMN IF EXISTS device_id;
+ALTER TABLE bids DROP COLUMN IF EXISTS ip_address;
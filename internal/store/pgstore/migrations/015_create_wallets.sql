@@ -0,0 +1,48 @@
+-- One balance per user and currency. held is the part of the balance
+-- committed to active bids; it can never exceed the balance.
+CREATE TABLE IF NOT EXISTS wallets (
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  currency CHAR(3) NOT NULL,
+  balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+  held BIGINT NOT NULL DEFAULT 0 CHECK (held >= 0 AND held <= balance),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, currency)
+);
+
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  currency CHAR(3) NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('deposit', 'withdrawal')),
+  amount BIGINT NOT NULL CHECK (amount > 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX wallet_transactions_user_id_idx ON wallet_transactions (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS bid_holds (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  bid_id UUID NOT NULL REFERENCES bids (id) ON DELETE CASCADE,
+  amount BIGINT NOT NULL CHECK (amount >= 0),
+  currency CHAR(3) NOT NULL,
+  status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'released')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  released_at TIMESTAMPTZ
+);
+
+-- A bidder holds funds for one bid per auction at most: raising the bid
+-- replaces the hold.
+CREATE UNIQUE INDEX bid_holds_active_idx ON bid_holds (user_id, product_id) WHERE status = 'active';
+CREATE INDEX bid_holds_bid_id_idx ON bid_holds (bid_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS bid_holds_bid_id_idx;
+DROP INDEX IF EXISTS bid_holds_active_idx;
+DROP TABLE IF EXISTS bid_holds;
+DROP INDEX IF EXISTS wallet_transactions_user_id_idx;
+DROP TABLE IF EXISTS wallet_transactions;
+DROP TABLE IF EXISTS wallets;
@@ -0,0 +1,7 @@
+CREATE INDEX IF NOT EXISTS products_auction_end_idx ON products (auction_end);
+CREATE INDEX IF NOT EXISTS products_created_at_idx ON products (created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS products_created_at_idx;
+DROP INDEX IF EXISTS products_auction_end_idx;
@@ -0,0 +1,24 @@
+-- starts_at lets listings be scheduled for later; existing auctions started
+-- when they were created.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS starts_at TIMESTAMPTZ;
+UPDATE products SET starts_at = created_at WHERE starts_at IS NULL;
+ALTER TABLE products ALTER COLUMN starts_at SET NOT NULL;
+ALTER TABLE products ALTER COLUMN starts_at SET DEFAULT now();
+
+-- The search vector indexes name and description with both the Portuguese
+-- and the English dictionaries, so a query parsed with either of them finds
+-- its stems. Names weigh more than descriptions in the ranking.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+  setweight(to_tsvector('portuguese', coalesce(product_name, '')), 'A') ||
+  setweight(to_tsvector('english', coalesce(product_name, '')), 'A') ||
+  setweight(to_tsvector('portuguese', coalesce(description, '')), 'B') ||
+  setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING GIN (search_vector);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS products_search_vector_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS starts_at;
@@ -0,0 +1,30 @@
+-- Categories form a tree through parent_id. Sibling names are unique, and
+-- top level categories count as siblings of each other.
+CREATE TABLE IF NOT EXISTS categories (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  parent_id UUID REFERENCES categories (id),
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE NULLS NOT DISTINCT (parent_id, name)
+);
+
+-- Products listed before categories existed have none.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS products_category_id_idx ON products (category_id);
+
+CREATE TABLE IF NOT EXISTS product_tags (
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  tag VARCHAR(30) NOT NULL,
+  PRIMARY KEY (product_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS product_tags_tag_idx ON product_tags (tag);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS product_tags;
+DROP INDEX IF EXISTS products_category_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
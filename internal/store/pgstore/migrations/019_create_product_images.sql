@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS product_images (
+  id UUID PRIMARY KEY NOT NULL,
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  content_type TEXT NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  blob_key TEXT NOT NULL,
+  thumbnail_key TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS product_images_product_id_position_idx ON product_images (product_id, position);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS product_images;
@@ -0,0 +1,79 @@
+-- A product may list several identical units. Bid amounts are prices per
+-- unit, and pricing decides what winners pay: the lowest winning bid for
+-- everyone (uniform) or their own bid (pay_as_bid).
+ALTER TABLE products ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS pricing TEXT NOT NULL DEFAULT 'uniform' CHECK (pricing IN ('uniform', 'pay_as_bid'));
+ALTER TABLE products ADD COLUMN IF NOT EXISTS finalized_at TIMESTAMPTZ;
+
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
+
+-- One row per winner of a finalized auction.
+CREATE TABLE IF NOT EXISTS auction_results (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  bidder_id UUID NOT NULL REFERENCES users (id),
+  bid_id UUID NOT NULL REFERENCES bids (id),
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  unit_price BIGINT NOT NULL,
+  total BIGINT NOT NULL,
+  currency CHAR(3) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (product_id, bidder_id)
+);
+
+CREATE INDEX IF NOT EXISTS products_unfinalized_idx ON products (auction_end) WHERE finalized_at IS NULL;
+
+-- Strictly increasing bids only make sense for single unit auctions. With
+-- several units a bid only has to beat the clearing price, which BidsService
+-- checks while holding the product lock.
+CREATE OR REPLACE FUNCTION enforce_increasing_bids() RETURNS TRIGGER AS $$
+DECLARE
+  highest BIGINT;
+  units INTEGER;
+BEGIN
+  SELECT quantity INTO units FROM products WHERE id = NEW.product_id FOR UPDATE;
+
+  IF units > 1 THEN
+    RETURN NEW;
+  END IF;
+
+  SELECT max(bid_amount) INTO highest
+  FROM bids
+  WHERE product_id = NEW.product_id AND voided_at IS NULL;
+
+  IF highest IS NOT NULL AND NEW.bid_amount <= highest THEN
+    RAISE EXCEPTION 'bid amount % must be greater than the highest bid %', NEW.bid_amount, highest
+      USING ERRCODE = 'check_violation';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+---- create above / drop below ----
+
+CREATE OR REPLACE FUNCTION enforce_increasing_bids() RETURNS TRIGGER AS $$
+DECLARE
+  highest BIGINT;
+BEGIN
+  PERFORM 1 FROM products WHERE id = NEW.product_id FOR UPDATE;
+
+  SELECT max(bid_amount) INTO highest
+  FROM bids
+  WHERE product_id = NEW.product_id AND voided_at IS NULL;
+
+  IF highest IS NOT NULL AND NEW.bid_amount <= highest THEN
+    RAISE EXCEPTION 'bid amount % must be greater than the highest bid %', NEW.bid_amount, highest
+      USING ERRCODE = 'check_violation';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS products_unfinalized_idx;
+DROP TABLE IF EXISTS auction_results;
+ALTER TABLE bids DROP COLUMN IF EXISTS quantity;
+ALTER TABLE products DROP COLUMN IF EXISTS finalized_at;
+ALTER TABLE products DROP COLUMN IF EXISTS pricing;
+ALTER TABLE products DROP COLUMN IF EXISTS quantity;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS watchlist (
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+  ending_soon_sent_at TIMESTAMPTZ,
+  ended_sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS watchlist_product_id_idx ON watchlist (product_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  product_id UUID REFERENCES products (id) ON DELETE SET NULL,
+  kind TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  body TEXT NOT NULL,
+  read_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS watchlist;
@@ -0,0 +1,12 @@
+-- Products without published_at are drafts: only their seller sees them and
+-- they never get an auction room. Existing products were published when
+-- they were created.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+UPDATE products SET published_at = created_at WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS products_drafts_idx ON products (seller_id, updated_at DESC) WHERE published_at IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS products_drafts_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS published_at;
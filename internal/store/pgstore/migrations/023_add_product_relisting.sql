@@ -0,0 +1,22 @@
+-- A reserve is the lowest unit price the seller accepts: units whose price
+-- ends below it are not sold. It is stored in minor units of the product
+-- currency, like base_price.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reserve_price BIGINT CHECK (reserve_price > 0);
+
+-- A relisted product points back to the product it was cloned from, and a
+-- product is relisted at most once so that its history stays a single chain.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS relisted_from_id UUID REFERENCES products (id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS products_relisted_from_id_idx ON products (relisted_from_id) WHERE relisted_from_id IS NOT NULL;
+
+-- Auto-relist policy: how many more times an unsold auction is relisted when
+-- it is finalized, and how much the base price drops each time.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS auto_relist_remaining INTEGER NOT NULL DEFAULT 0 CHECK (auto_relist_remaining >= 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS auto_relist_drop_percent INTEGER NOT NULL DEFAULT 0 CHECK (auto_relist_drop_percent BETWEEN 0 AND 90);
+
+---- create above / drop below ----
+
+ALTER TABLE products DROP COLUMN IF EXISTS auto_relist_drop_percent;
+ALTER TABLE products DROP COLUMN IF EXISTS auto_relist_remaining;
+DROP INDEX IF EXISTS products_relisted_from_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS relisted_from_id;
+ALTER TABLE products DROP COLUMN IF EXISTS reserve_price;
@@ -0,0 +1,16 @@
+-- Password reset tokens are random and only their SHA-256 is stored, so a
+-- copy of the table cannot be used to take over accounts.
+CREATE TABLE IF NOT EXISTS password_resets (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  token_hash BYTEA UNIQUE NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS password_resets_user_id_idx ON password_resets (user_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS password_resets;
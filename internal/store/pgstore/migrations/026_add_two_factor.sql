@@ -0,0 +1,27 @@
+-- TOTP secret of users who enrolled in two-factor authentication. The row
+-- exists as soon as enrollment starts and counts only once enabled_at is
+-- set. last_step is the time step of the last accepted code, so a code
+-- cannot be used twice.
+CREATE TABLE IF NOT EXISTS user_totp (
+  user_id UUID PRIMARY KEY NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  secret BYTEA NOT NULL,
+  enabled_at TIMESTAMPTZ,
+  last_step BIGINT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Single-use recovery codes for when the authenticator is lost. Only their
+-- SHA-256 is stored.
+CREATE TABLE IF NOT EXISTS recovery_codes (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  code_hash BYTEA NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, code_hash)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totp;
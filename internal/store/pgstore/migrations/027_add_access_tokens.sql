@@ -0,0 +1,18 @@
+-- Personal access tokens let scripts authenticate with a bearer token
+-- instead of a cookie session. Only the SHA-256 of a token is stored, and
+-- each one is limited to the scopes it was created with.
+CREATE TABLE IF NOT EXISTS access_tokens (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  token_hash BYTEA UNIQUE NOT NULL,
+  scopes TEXT[] NOT NULL CHECK (cardinality(scopes) > 0 AND scopes <@ ARRAY['read', 'bid', 'sell']),
+  expires_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS access_tokens;
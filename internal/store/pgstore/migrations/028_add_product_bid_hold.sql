@@ -0,0 +1,7 @@
+-- Share of each bid held in the bidder's wallet while it stands. Sellers opt
+-- in per product; zero places no hold.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS bid_hold_percent INTEGER NOT NULL DEFAULT 0 CHECK (bid_hold_percent BETWEEN 0 AND 100);
+
+---- create above / drop below ----
+
+ALTER TABLE products DROP COLUMN IF EXISTS bid_hold_percent;
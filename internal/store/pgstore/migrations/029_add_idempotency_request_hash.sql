@@ -0,0 +1,7 @@
+-- Hash of the method, path and body of the request that claimed the key, so
+-- reusing a key for a different request can be refused.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS request_hash BYTEA NOT NULL DEFAULT '';
+
+---- create above / drop below ----
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS request_hash;
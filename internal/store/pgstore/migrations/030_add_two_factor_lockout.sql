@@ -0,0 +1,10 @@
+-- Invalid second factor codes in a row. Reaching the limit locks the second
+-- factor of the user until locked_until, however many sessions the codes
+-- came from.
+ALTER TABLE user_totp ADD COLUMN IF NOT EXISTS failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+---- create above / drop below ----
+
+ALTER TABLE user_totp DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE user_totp DROP COLUMN IF EXISTS failed_attempts;
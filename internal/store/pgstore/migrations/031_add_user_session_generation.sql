@@ -0,0 +1,7 @@
+-- Sessions remember the generation of their user at login and stop being
+-- valid once it moves on, so every session of a user can be ended at once.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS session_generation INTEGER NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE users DROP COLUMN IF EXISTS session_generation;
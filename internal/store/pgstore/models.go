@@ -5,29 +5,184 @@
 package pgstore
 
 import (
+	"net/netip"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Bid struct {
+type AccessToken struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  []byte             `json:"token_hash"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type AuctionResult struct {
 	ID        uuid.UUID `json:"id"`
 	ProductID uuid.UUID `json:"product_id"`
 	BidderID  uuid.UUID `json:"bidder_id"`
-	BidAmount float64   `json:"bid_amount"`
+	BidID     uuid.UUID `json:"bid_id"`
+	Quantity  int32     `json:"quantity"`
+	UnitPrice int64     `json:"unit_price"`
+	Total     int64     `json:"total"`
+	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type Product struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
-	IsSold      bool      `json:"is_sold"`
+type Bid struct {
+	ID        uuid.UUID          `json:"id"`
+	ProductID uuid.UUID          `json:"product_id"`
+	BidderID  uuid.UUID          `json:"bidder_id"`
+	BidAmount int64              `json:"bid_amount"`
+	CreatedAt time.Time          `json:"created_at"`
+	VoidedAt  pgtype.Timestamptz `json:"voided_at"`
+	IpAddress *netip.Addr        `json:"ip_address"`
+	DeviceID  pgtype.Text        `json:"device_id"`
+	Quantity  int32              `json:"quantity"`
+}
+
+type BidHold struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	ProductID  uuid.UUID          `json:"product_id"`
+	BidID      uuid.UUID          `json:"bid_id"`
+	Amount     int64              `json:"amount"`
+	Currency   string             `json:"currency"`
+	Status     string             `json:"status"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ReleasedAt pgtype.Timestamptz `json:"released_at"`
+}
+
+type BidRetraction struct {
+	ID          uuid.UUID          `json:"id"`
+	BidID       uuid.UUID          `json:"bid_id"`
+	RequesterID uuid.UUID          `json:"requester_id"`
+	Reason      string             `json:"reason"`
+	Status      string             `json:"status"`
+	ReviewerID  pgtype.UUID        `json:"reviewer_id"`
+	ReviewedAt  pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type BidderPseudonym struct {
+	ProductID uuid.UUID `json:"product_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Number    int32     `json:"number"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Category struct {
+	ID        uuid.UUID   `json:"id"`
+	ParentID  pgtype.UUID `json:"parent_id"`
+	Name      string      `json:"name"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type EmailVerification struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	StatusCode  int32     `json:"status_code"`
+	Response    []byte    `json:"response"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	RequestHash []byte    `json:"request_hash"`
+}
+
+type Notification struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ProductID pgtype.UUID        `json:"product_id"`
+	Kind      string             `json:"kind"`
+	Subject   string             `json:"subject"`
+	Body      string             `json:"body"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type PasswordReset struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	TokenHash []byte             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type Product struct {
+	ID                    uuid.UUID          `json:"id"`
+	SellerID              uuid.UUID          `json:"seller_id"`
+	ProductName           string             `json:"product_name"`
+	Description           string             `json:"description"`
+	BasePrice             int64              `json:"base_price"`
+	AuctionEnd            time.Time          `json:"auction_end"`
+	IsSold                bool               `json:"is_sold"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Currency              string             `json:"currency"`
+	StartsAt              time.Time          `json:"starts_at"`
+	SearchVector          interface{}        `json:"search_vector"`
+	CategoryID            pgtype.UUID        `json:"category_id"`
+	Quantity              int32              `json:"quantity"`
+	Pricing               string             `json:"pricing"`
+	FinalizedAt           pgtype.Timestamptz `json:"finalized_at"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+	ReservePrice          pgtype.Int8        `json:"reserve_price"`
+	RelistedFromID        pgtype.UUID        `json:"relisted_from_id"`
+	AutoRelistRemaining   int32              `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32              `json:"auto_relist_drop_percent"`
+	BidHoldPercent        int32              `json:"bid_hold_percent"`
+}
+
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	Position     int32     `json:"position"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	SizeBytes    int64     `json:"size_bytes"`
+	BlobKey      string    `json:"blob_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ProductTag struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Tag       string    `json:"tag"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  []byte             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type RoomMessage struct {
+	ID        uuid.UUID          `json:"id"`
+	ProductID uuid.UUID          `json:"product_id"`
+	SenderID  uuid.UUID          `json:"sender_id"`
+	Content   string             `json:"content"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy pgtype.UUID        `json:"deleted_by"`
+	CreatedAt time.Time          `json:"created_at"`
 }
 
 type Session struct {
@@ -36,12 +191,74 @@ type Session struct {
 	Expiry time.Time `json:"expiry"`
 }
 
+type ShillFlag struct {
+	ID         uuid.UUID          `json:"id"`
+	BidderID   uuid.UUID          `json:"bidder_id"`
+	SellerID   uuid.UUID          `json:"seller_id"`
+	Rule       string             `json:"rule"`
+	Score      int32              `json:"score"`
+	Details    string             `json:"details"`
+	Status     string             `json:"status"`
+	ReviewerID pgtype.UUID        `json:"reviewer_id"`
+	ReviewedAt pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	UserName     string    `json:"user_name"`
-	Email        string    `json:"email"`
-	PasswordHash []byte    `json:"password_hash"`
-	Bio          string    `json:"bio"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uuid.UUID          `json:"id"`
+	UserName          string             `json:"user_name"`
+	Email             string             `json:"email"`
+	PasswordHash      []byte             `json:"password_hash"`
+	Bio               string             `json:"bio"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	IsAdmin           bool               `json:"is_admin"`
+	RetractionCount   int32              `json:"retraction_count"`
+	EmailVerifiedAt   pgtype.Timestamptz `json:"email_verified_at"`
+	SessionGeneration int32              `json:"session_generation"`
+}
+
+type UserOrigin struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	IpAddress   netip.Addr `json:"ip_address"`
+	DeviceID    string     `json:"device_id"`
+	FirstSeenAt time.Time  `json:"first_seen_at"`
+	LastSeenAt  time.Time  `json:"last_seen_at"`
+}
+
+type UserTotp struct {
+	UserID         uuid.UUID          `json:"user_id"`
+	Secret         []byte             `json:"secret"`
+	EnabledAt      pgtype.Timestamptz `json:"enabled_at"`
+	LastStep       pgtype.Int8        `json:"last_step"`
+	CreatedAt      time.Time          `json:"created_at"`
+	FailedAttempts int32              `json:"failed_attempts"`
+	LockedUntil    pgtype.Timestamptz `json:"locked_until"`
+}
+
+type Wallet struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Currency  string    `json:"currency"`
+	Balance   int64     `json:"balance"`
+	Held      int64     `json:"held"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WalletTransaction struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Currency  string    `json:"currency"`
+	Kind      string    `json:"kind"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Watchlist struct {
+	UserID           uuid.UUID          `json:"user_id"`
+	ProductID        uuid.UUID          `json:"product_id"`
+	EndingSoonSentAt pgtype.Timestamptz `json:"ending_soon_sent_at"`
+	EndedSentAt      pgtype.Timestamptz `json:"ended_sent_at"`
+	CreatedAt        time.Time          `json:"created_at"`
 }
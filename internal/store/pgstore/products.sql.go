@@ -10,37 +10,209 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countProducts = `-- name: CountProducts :one
+SELECT COUNT(*)
+FROM products
+WHERE products.published_at IS NOT NULL
+  AND ($1::text = 'all'
+  OR ($1 = 'live' AND products.starts_at <= now() AND products.auction_end > now())
+  OR ($1 = 'ended' AND products.auction_end <= now())
+  OR ($1 = 'upcoming' AND products.starts_at > now()))
+  AND ($2::uuid IS NULL OR products.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT categories.id FROM categories WHERE categories.id = $2
+      UNION ALL
+      SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($3::text IS NULL OR EXISTS (
+    SELECT 1 FROM product_tags WHERE product_tags.product_id = products.id AND product_tags.tag = $3
+  ))
+`
+
+type CountProductsParams struct {
+	Status     string      `json:"status"`
+	CategoryID pgtype.UUID `json:"category_id"`
+	Tag        pgtype.Text `json:"tag"`
+}
+
+func (q *Queries) CountProducts(ctx context.Context, arg CountProductsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countProducts, arg.Status, arg.CategoryID, arg.Tag)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProduct = `-- name: CreateProduct :one
-INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end")
-VALUES ($1, $2, $3, $4, $5) 
-RETURNING id
+INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end", "currency", "category_id", "quantity", "pricing", "starts_at", "reserve_price")
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
 `
 
 type CreateProductParams struct {
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	SellerID     uuid.UUID   `json:"seller_id"`
+	ProductName  string      `json:"product_name"`
+	Description  string      `json:"description"`
+	BasePrice    int64       `json:"base_price"`
+	AuctionEnd   time.Time   `json:"auction_end"`
+	Currency     string      `json:"currency"`
+	CategoryID   pgtype.UUID `json:"category_id"`
+	Quantity     int32       `json:"quantity"`
+	Pricing      string      `json:"pricing"`
+	StartsAt     time.Time   `json:"starts_at"`
+	ReservePrice pgtype.Int8 `json:"reserve_price"`
 }
 
-func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (uuid.UUID, error) {
+func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
 	row := q.db.QueryRow(ctx, createProduct,
 		arg.SellerID,
 		arg.ProductName,
 		arg.Description,
 		arg.BasePrice,
 		arg.AuctionEnd,
+		arg.Currency,
+		arg.CategoryID,
+		arg.Quantity,
+		arg.Pricing,
+		arg.StartsAt,
+		arg.ReservePrice,
 	)
-	var id uuid.UUID
-	err := row.Scan(&id)
-	return id, err
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const createRelistedProduct = `-- name: CreateRelistedProduct :one
+INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end", "currency", "category_id", "quantity", "pricing", "starts_at", "reserve_price", "relisted_from_id", "auto_relist_remaining", "auto_relist_drop_percent", "bid_hold_percent", "published_at")
+SELECT seller_id, product_name, description, $1, $2, currency, category_id, quantity, pricing, now(), reserve_price, id, $3, auto_relist_drop_percent, bid_hold_percent, now()
+FROM products
+WHERE id = $4
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+`
+
+type CreateRelistedProductParams struct {
+	BasePrice           int64     `json:"base_price"`
+	AuctionEnd          time.Time `json:"auction_end"`
+	AutoRelistRemaining int32     `json:"auto_relist_remaining"`
+	ID                  uuid.UUID `json:"id"`
+}
+
+func (q *Queries) CreateRelistedProduct(ctx context.Context, arg CreateRelistedProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, createRelistedProduct,
+		arg.BasePrice,
+		arg.AuctionEnd,
+		arg.AutoRelistRemaining,
+		arg.ID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const deleteProduct = `-- name: DeleteProduct :exec
+DELETE FROM products
+WHERE id = $1
+`
+
+func (q *Queries) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProduct, id)
+	return err
+}
+
+const finalizeProduct = `-- name: FinalizeProduct :one
+UPDATE products
+SET finalized_at = now(), is_sold = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+`
+
+type FinalizeProductParams struct {
+	ID     uuid.UUID `json:"id"`
+	IsSold bool      `json:"is_sold"`
+}
+
+func (q *Queries) FinalizeProduct(ctx context.Context, arg FinalizeProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, finalizeProduct, arg.ID, arg.IsSold)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
 }
 
 const getProductById = `-- name: GetProductById :one
-SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at 
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent 
 FROM products 
 WHERE id = $1
 `
@@ -58,6 +230,1005 @@ func (q *Queries) GetProductById(ctx context.Context, id uuid.UUID) (Product, er
 		&i.IsSold,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const getProductByIdForUpdate = `-- name: GetProductByIdForUpdate :one
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent 
+FROM products 
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetProductByIdForUpdate(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductByIdForUpdate, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const getProductWithStatsById = `-- name: GetProductWithStatsById :one
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.id = $1
+`
+
+type GetProductWithStatsByIdRow struct {
+	ID                    uuid.UUID          `json:"id"`
+	SellerID              uuid.UUID          `json:"seller_id"`
+	ProductName           string             `json:"product_name"`
+	Description           string             `json:"description"`
+	BasePrice             int64              `json:"base_price"`
+	AuctionEnd            time.Time          `json:"auction_end"`
+	IsSold                bool               `json:"is_sold"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Currency              string             `json:"currency"`
+	StartsAt              time.Time          `json:"starts_at"`
+	CategoryID            pgtype.UUID        `json:"category_id"`
+	Quantity              int32              `json:"quantity"`
+	Pricing               string             `json:"pricing"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+	ReservePrice          pgtype.Int8        `json:"reserve_price"`
+	RelistedFromID        pgtype.UUID        `json:"relisted_from_id"`
+	AutoRelistRemaining   int32              `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32              `json:"auto_relist_drop_percent"`
+	BidHoldPercent        int32              `json:"bid_hold_percent"`
+	CurrentPrice          int64              `json:"current_price"`
+	BidCount              int32              `json:"bid_count"`
+	WatchCount            int32              `json:"watch_count"`
+}
+
+func (q *Queries) GetProductWithStatsById(ctx context.Context, id uuid.UUID) (GetProductWithStatsByIdRow, error) {
+	row := q.db.QueryRow(ctx, getProductWithStatsById, id)
+	var i GetProductWithStatsByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+		&i.CurrentPrice,
+		&i.BidCount,
+		&i.WatchCount,
+	)
+	return i, err
+}
+
+const getRelistOf = `-- name: GetRelistOf :one
+SELECT id
+FROM products
+WHERE relisted_from_id = $1
+`
+
+func (q *Queries) GetRelistOf(ctx context.Context, relistedFromID uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getRelistOf, relistedFromID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listDraftsBySellerId = `-- name: ListDraftsBySellerId :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.seller_id = $1 AND products.published_at IS NULL
+ORDER BY products.updated_at DESC, products.id
+`
+
+type ListDraftsBySellerIdRow struct {
+	ID                    uuid.UUID          `json:"id"`
+	SellerID              uuid.UUID          `json:"seller_id"`
+	ProductName           string             `json:"product_name"`
+	Description           string             `json:"description"`
+	BasePrice             int64              `json:"base_price"`
+	AuctionEnd            time.Time          `json:"auction_end"`
+	IsSold                bool               `json:"is_sold"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Currency              string             `json:"currency"`
+	StartsAt              time.Time          `json:"starts_at"`
+	CategoryID            pgtype.UUID        `json:"category_id"`
+	Quantity              int32              `json:"quantity"`
+	Pricing               string             `json:"pricing"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+	ReservePrice          pgtype.Int8        `json:"reserve_price"`
+	RelistedFromID        pgtype.UUID        `json:"relisted_from_id"`
+	AutoRelistRemaining   int32              `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32              `json:"auto_relist_drop_percent"`
+	BidHoldPercent        int32              `json:"bid_hold_percent"`
+	CurrentPrice          int64              `json:"current_price"`
+	BidCount              int32              `json:"bid_count"`
+	WatchCount            int32              `json:"watch_count"`
+}
+
+func (q *Queries) ListDraftsBySellerId(ctx context.Context, sellerID uuid.UUID) ([]ListDraftsBySellerIdRow, error) {
+	rows, err := q.db.Query(ctx, listDraftsBySellerId, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDraftsBySellerIdRow
+	for rows.Next() {
+		var i ListDraftsBySellerIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Currency,
+			&i.StartsAt,
+			&i.CategoryID,
+			&i.Quantity,
+			&i.Pricing,
+			&i.PublishedAt,
+			&i.ReservePrice,
+			&i.RelistedFromID,
+			&i.AutoRelistRemaining,
+			&i.AutoRelistDropPercent,
+			&i.BidHoldPercent,
+			&i.CurrentPrice,
+			&i.BidCount,
+			&i.WatchCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProducts = `-- name: ListProducts :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.published_at IS NOT NULL
+  AND ($1::text = 'all'
+  OR ($1 = 'live' AND products.starts_at <= now() AND products.auction_end > now())
+  OR ($1 = 'ended' AND products.auction_end <= now())
+  OR ($1 = 'upcoming' AND products.starts_at > now()))
+  AND ($2::uuid IS NULL OR products.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT categories.id FROM categories WHERE categories.id = $2
+      UNION ALL
+      SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($3::text IS NULL OR EXISTS (
+    SELECT 1 FROM product_tags WHERE product_tags.product_id = products.id AND product_tags.tag = $3
+  ))
+ORDER BY
+  CASE WHEN $4::text = 'ending_soon' THEN products.auction_end END ASC,
+  CASE WHEN $4 = 'newest' THEN products.created_at END DESC,
+  CASE WHEN $4 = 'price_asc' THEN COALESCE(book.clearing_price, products.base_price) END ASC,
+  CASE WHEN $4 = 'price_desc' THEN COALESCE(book.clearing_price, products.base_price) END DESC,
+  products.id
+LIMIT $5 OFFSET $6
+`
+
+type ListProductsParams struct {
+	Status     string      `json:"status"`
+	CategoryID pgtype.UUID `json:"category_id"`
+	Tag        pgtype.Text `json:"tag"`
+	Sort       string      `json:"sort"`
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+}
+
+type ListProductsRow struct {
+	ID                    uuid.UUID          `json:"id"`
+	SellerID              uuid.UUID          `json:"seller_id"`
+	ProductName           string             `json:"product_name"`
+	Description           string             `json:"description"`
+	BasePrice             int64              `json:"base_price"`
+	AuctionEnd            time.Time          `json:"auction_end"`
+	IsSold                bool               `json:"is_sold"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Currency              string             `json:"currency"`
+	StartsAt              time.Time          `json:"starts_at"`
+	CategoryID            pgtype.UUID        `json:"category_id"`
+	Quantity              int32              `json:"quantity"`
+	Pricing               string             `json:"pricing"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+	ReservePrice          pgtype.Int8        `json:"reserve_price"`
+	RelistedFromID        pgtype.UUID        `json:"relisted_from_id"`
+	AutoRelistRemaining   int32              `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32              `json:"auto_relist_drop_percent"`
+	BidHoldPercent        int32              `json:"bid_hold_percent"`
+	CurrentPrice          int64              `json:"current_price"`
+	BidCount              int32              `json:"bid_count"`
+	WatchCount            int32              `json:"watch_count"`
+}
+
+func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]ListProductsRow, error) {
+	rows, err := q.db.Query(ctx, listProducts,
+		arg.Status,
+		arg.CategoryID,
+		arg.Tag,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsRow
+	for rows.Next() {
+		var i ListProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Currency,
+			&i.StartsAt,
+			&i.CategoryID,
+			&i.Quantity,
+			&i.Pricing,
+			&i.PublishedAt,
+			&i.ReservePrice,
+			&i.RelistedFromID,
+			&i.AutoRelistRemaining,
+			&i.AutoRelistDropPercent,
+			&i.BidHoldPercent,
+			&i.CurrentPrice,
+			&i.BidCount,
+			&i.WatchCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsWithStatsByIds = `-- name: ListProductsWithStatsByIds :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.id = ANY($1::uuid[])
+`
+
+type ListProductsWithStatsByIdsRow struct {
+	ID                    uuid.UUID          `json:"id"`
+	SellerID              uuid.UUID          `json:"seller_id"`
+	ProductName           string             `json:"product_name"`
+	Description           string             `json:"description"`
+	BasePrice             int64              `json:"base_price"`
+	AuctionEnd            time.Time          `json:"auction_end"`
+	IsSold                bool               `json:"is_sold"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Currency              string             `json:"currency"`
+	StartsAt              time.Time          `json:"starts_at"`
+	CategoryID            pgtype.UUID        `json:"category_id"`
+	Quantity              int32              `json:"quantity"`
+	Pricing               string             `json:"pricing"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+	ReservePrice          pgtype.Int8        `json:"reserve_price"`
+	RelistedFromID        pgtype.UUID        `json:"relisted_from_id"`
+	AutoRelistRemaining   int32              `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32              `json:"auto_relist_drop_percent"`
+	BidHoldPercent        int32              `json:"bid_hold_percent"`
+	CurrentPrice          int64              `json:"current_price"`
+	BidCount              int32              `json:"bid_count"`
+	WatchCount            int32              `json:"watch_count"`
+}
+
+func (q *Queries) ListProductsWithStatsByIds(ctx context.Context, ids []uuid.UUID) ([]ListProductsWithStatsByIdsRow, error) {
+	rows, err := q.db.Query(ctx, listProductsWithStatsByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsWithStatsByIdsRow
+	for rows.Next() {
+		var i ListProductsWithStatsByIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Currency,
+			&i.StartsAt,
+			&i.CategoryID,
+			&i.Quantity,
+			&i.Pricing,
+			&i.PublishedAt,
+			&i.ReservePrice,
+			&i.RelistedFromID,
+			&i.AutoRelistRemaining,
+			&i.AutoRelistDropPercent,
+			&i.BidHoldPercent,
+			&i.CurrentPrice,
+			&i.BidCount,
+			&i.WatchCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRelistChain = `-- name: ListRelistChain :many
+WITH RECURSIVE ancestors AS (
+  SELECT products.id, products.relisted_from_id FROM products WHERE products.id = $1
+  UNION ALL
+  SELECT products.id, products.relisted_from_id FROM products JOIN ancestors ON products.id = ancestors.relisted_from_id
+), chain AS (
+  SELECT ancestors.id FROM ancestors WHERE ancestors.relisted_from_id IS NULL
+  UNION ALL
+  SELECT products.id FROM products JOIN chain ON products.relisted_from_id = chain.id
+)
+SELECT products.id, products.base_price, products.currency, products.starts_at, products.auction_end, products.is_sold, products.finalized_at, products.relisted_from_id
+FROM products
+JOIN chain ON chain.id = products.id
+ORDER BY products.created_at, products.id
+`
+
+type ListRelistChainRow struct {
+	ID             uuid.UUID          `json:"id"`
+	BasePrice      int64              `json:"base_price"`
+	Currency       string             `json:"currency"`
+	StartsAt       time.Time          `json:"starts_at"`
+	AuctionEnd     time.Time          `json:"auction_end"`
+	IsSold         bool               `json:"is_sold"`
+	FinalizedAt    pgtype.Timestamptz `json:"finalized_at"`
+	RelistedFromID pgtype.UUID        `json:"relisted_from_id"`
+}
+
+func (q *Queries) ListRelistChain(ctx context.Context, id uuid.UUID) ([]ListRelistChainRow, error) {
+	rows, err := q.db.Query(ctx, listRelistChain, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRelistChainRow
+	for rows.Next() {
+		var i ListRelistChainRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BasePrice,
+			&i.Currency,
+			&i.StartsAt,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.FinalizedAt,
+			&i.RelistedFromID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStartedProducts = `-- name: ListStartedProducts :many
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+FROM products
+WHERE published_at IS NOT NULL AND starts_at <= now() AND auction_end > now()
+ORDER BY auction_end
+`
+
+func (q *Queries) ListStartedProducts(ctx context.Context) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listStartedProducts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Currency,
+			&i.StartsAt,
+			&i.SearchVector,
+			&i.CategoryID,
+			&i.Quantity,
+			&i.Pricing,
+			&i.FinalizedAt,
+			&i.PublishedAt,
+			&i.ReservePrice,
+			&i.RelistedFromID,
+			&i.AutoRelistRemaining,
+			&i.AutoRelistDropPercent,
+			&i.BidHoldPercent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnfinalizedEndedProductIds = `-- name: ListUnfinalizedEndedProductIds :many
+SELECT id
+FROM products
+WHERE finalized_at IS NULL AND published_at IS NOT NULL AND auction_end <= now()
+ORDER BY auction_end
+LIMIT $1
+`
+
+func (q *Queries) ListUnfinalizedEndedProductIds(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listUnfinalizedEndedProductIds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWatchedProducts = `-- name: ListWatchedProducts :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+JOIN watchlist ON watchlist.product_id = products.id
+WHERE watchlist.user_id = $1
+ORDER BY products.auction_end, products.id
+`
+
+type ListWatchedProductsRow struct {
+	ID                    uuid.UUID          `json:"id"`
+	SellerID              uuid.UUID          `json:"seller_id"`
+	ProductName           string             `json:"product_name"`
+	Description           string             `json:"description"`
+	BasePrice             int64              `json:"base_price"`
+	AuctionEnd            time.Time          `json:"auction_end"`
+	IsSold                bool               `json:"is_sold"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	Currency              string             `json:"currency"`
+	StartsAt              time.Time          `json:"starts_at"`
+	CategoryID            pgtype.UUID        `json:"category_id"`
+	Quantity              int32              `json:"quantity"`
+	Pricing               string             `json:"pricing"`
+	PublishedAt           pgtype.Timestamptz `json:"published_at"`
+	ReservePrice          pgtype.Int8        `json:"reserve_price"`
+	RelistedFromID        pgtype.UUID        `json:"relisted_from_id"`
+	AutoRelistRemaining   int32              `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32              `json:"auto_relist_drop_percent"`
+	BidHoldPercent        int32              `json:"bid_hold_percent"`
+	CurrentPrice          int64              `json:"current_price"`
+	BidCount              int32              `json:"bid_count"`
+	WatchCount            int32              `json:"watch_count"`
+}
+
+func (q *Queries) ListWatchedProducts(ctx context.Context, userID uuid.UUID) ([]ListWatchedProductsRow, error) {
+	rows, err := q.db.Query(ctx, listWatchedProducts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWatchedProductsRow
+	for rows.Next() {
+		var i ListWatchedProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Currency,
+			&i.StartsAt,
+			&i.CategoryID,
+			&i.Quantity,
+			&i.Pricing,
+			&i.PublishedAt,
+			&i.ReservePrice,
+			&i.RelistedFromID,
+			&i.AutoRelistRemaining,
+			&i.AutoRelistDropPercent,
+			&i.BidHoldPercent,
+			&i.CurrentPrice,
+			&i.BidCount,
+			&i.WatchCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishProduct = `-- name: PublishProduct :one
+UPDATE products
+SET published_at = now(), starts_at = $2, updated_at = now()
+WHERE id = $1 AND published_at IS NULL
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+`
+
+type PublishProductParams struct {
+	ID       uuid.UUID `json:"id"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+func (q *Queries) PublishProduct(ctx context.Context, arg PublishProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, publishProduct, arg.ID, arg.StartsAt)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const searchProducts = `-- name: SearchProducts :many
+SELECT products.id,
+       ts_rank(products.search_vector, search.query)::real AS rank,
+       ts_headline($1::text::regconfig, products.product_name, search.query, format('StartSel="%s", StopSel="%s", HighlightAll=true', chr(57344), chr(57345)))::text AS name_snippet,
+       ts_headline($1::text::regconfig, products.description, search.query, format('StartSel="%s", StopSel="%s", MinWords=10, MaxWords=30', chr(57344), chr(57345)))::text AS description_snippet
+FROM products
+CROSS JOIN (SELECT to_tsquery($1::text::regconfig, $2::text) AS query) search
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.search_vector @@ search.query
+  AND products.published_at IS NOT NULL
+  AND ($3::bigint IS NULL OR COALESCE(book.clearing_price, products.base_price) >= $3)
+  AND ($4::bigint IS NULL OR COALESCE(book.clearing_price, products.base_price) <= $4)
+  AND ($5::text IS NULL OR products.currency = $5)
+  AND ($6::text = 'all'
+    OR ($6 = 'live' AND products.starts_at <= now() AND products.auction_end > now())
+    OR ($6 = 'ended' AND products.auction_end <= now())
+    OR ($6 = 'upcoming' AND products.starts_at > now()))
+  AND ($7::timestamptz IS NULL OR products.auction_end < $7)
+ORDER BY rank DESC, products.auction_end
+LIMIT $8 OFFSET $9
+`
+
+type SearchProductsParams struct {
+	Language     string             `json:"language"`
+	Query        string             `json:"query"`
+	MinPrice     pgtype.Int8        `json:"min_price"`
+	MaxPrice     pgtype.Int8        `json:"max_price"`
+	Currency     pgtype.Text        `json:"currency"`
+	Status       string             `json:"status"`
+	EndingBefore pgtype.Timestamptz `json:"ending_before"`
+	Limit        int32              `json:"limit"`
+	Offset       int32              `json:"offset"`
+}
+
+type SearchProductsRow struct {
+	ID                 uuid.UUID `json:"id"`
+	Rank               float32   `json:"rank"`
+	NameSnippet        string    `json:"name_snippet"`
+	DescriptionSnippet string    `json:"description_snippet"`
+}
+
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
+	rows, err := q.db.Query(ctx, searchProducts,
+		arg.Language,
+		arg.Query,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Currency,
+		arg.Status,
+		arg.EndingBefore,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsRow
+	for rows.Next() {
+		var i SearchProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Rank,
+			&i.NameSnippet,
+			&i.DescriptionSnippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProductBidHold = `-- name: SetProductBidHold :one
+UPDATE products
+SET bid_hold_percent = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+`
+
+type SetProductBidHoldParams struct {
+	ID             uuid.UUID `json:"id"`
+	BidHoldPercent int32     `json:"bid_hold_percent"`
+}
+
+func (q *Queries) SetProductBidHold(ctx context.Context, arg SetProductBidHoldParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductBidHold, arg.ID, arg.BidHoldPercent)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const setProductRelistPolicy = `-- name: SetProductRelistPolicy :one
+UPDATE products
+SET auto_relist_remaining = $2, auto_relist_drop_percent = $3, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+`
+
+type SetProductRelistPolicyParams struct {
+	ID                    uuid.UUID `json:"id"`
+	AutoRelistRemaining   int32     `json:"auto_relist_remaining"`
+	AutoRelistDropPercent int32     `json:"auto_relist_drop_percent"`
+}
+
+func (q *Queries) SetProductRelistPolicy(ctx context.Context, arg SetProductRelistPolicyParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductRelistPolicy, arg.ID, arg.AutoRelistRemaining, arg.AutoRelistDropPercent)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
+	)
+	return i, err
+}
+
+const updateProduct = `-- name: UpdateProduct :one
+UPDATE products
+SET product_name = $2, description = $3, base_price = $4, currency = $5, auction_end = $6, starts_at = $7, category_id = $8, quantity = $9, pricing = $10, reserve_price = $11, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+`
+
+type UpdateProductParams struct {
+	ID           uuid.UUID   `json:"id"`
+	ProductName  string      `json:"product_name"`
+	Description  string      `json:"description"`
+	BasePrice    int64       `json:"base_price"`
+	Currency     string      `json:"currency"`
+	AuctionEnd   time.Time   `json:"auction_end"`
+	StartsAt     time.Time   `json:"starts_at"`
+	CategoryID   pgtype.UUID `json:"category_id"`
+	Quantity     int32       `json:"quantity"`
+	Pricing      string      `json:"pricing"`
+	ReservePrice pgtype.Int8 `json:"reserve_price"`
+}
+
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProduct,
+		arg.ID,
+		arg.ProductName,
+		arg.Description,
+		arg.BasePrice,
+		arg.Currency,
+		arg.AuctionEnd,
+		arg.StartsAt,
+		arg.CategoryID,
+		arg.Quantity,
+		arg.Pricing,
+		arg.ReservePrice,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StartsAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.Quantity,
+		&i.Pricing,
+		&i.FinalizedAt,
+		&i.PublishedAt,
+		&i.ReservePrice,
+		&i.RelistedFromID,
+		&i.AutoRelistRemaining,
+		&i.AutoRelistDropPercent,
+		&i.BidHoldPercent,
 	)
 	return i, err
 }
@@ -0,0 +1,23 @@
+
+-- name: CreateAccessToken :one
+INSERT INTO access_tokens ("user_id", "name", "token_hash", "scopes", "expires_at")
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at;
+
+-- name: GetAccessTokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at FROM access_tokens WHERE token_hash = $1 AND expires_at > now();
+
+-- name: TouchAccessToken :exec
+UPDATE access_tokens SET last_used_at = now()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - INTERVAL '1 minute');
+
+-- name: ListAccessTokens :many
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at FROM access_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteAccessToken :execrows
+DELETE FROM access_tokens WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteUserAccessTokens :exec
+DELETE FROM access_tokens WHERE user_id = $1;
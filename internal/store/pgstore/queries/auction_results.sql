@@ -0,0 +1,13 @@
+-- name: CreateAuctionResult :one
+INSERT INTO auction_results ("product_id", "bidder_id", "bid_id", "quantity", "unit_price", "total", "currency")
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, product_id, bidder_id, bid_id, quantity, unit_price, total, currency, created_at;
+
+-- name: ListAuctionResultsByProductId :many
+SELECT auction_results.id, auction_results.product_id, auction_results.bidder_id, auction_results.bid_id, auction_results.quantity, auction_results.unit_price, auction_results.total, auction_results.currency, auction_results.created_at,
+       users.user_name AS bidder_name, bidder_pseudonyms.number AS bidder_number
+FROM auction_results
+JOIN users ON users.id = auction_results.bidder_id
+JOIN bidder_pseudonyms ON bidder_pseudonyms.product_id = auction_results.product_id AND bidder_pseudonyms.user_id = auction_results.bidder_id
+WHERE auction_results.product_id = $1
+ORDER BY auction_results.unit_price DESC, auction_results.created_at;
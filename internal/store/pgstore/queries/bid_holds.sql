@@ -0,0 +1,33 @@
+-- name: CreateBidHold :one
+INSERT INTO bid_holds ("user_id", "product_id", "bid_id", "amount", "currency")
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, product_id, bid_id, amount, currency, status, created_at, released_at;
+
+-- name: GetActiveBidHold :one
+SELECT id, user_id, product_id, bid_id, amount, currency, status, created_at, released_at
+FROM bid_holds
+WHERE user_id = $1 AND product_id = $2 AND status = 'active';
+
+-- name: GetActiveBidHoldByBidId :one
+SELECT id, user_id, product_id, bid_id, amount, currency, status, created_at, released_at
+FROM bid_holds
+WHERE bid_id = $1 AND status = 'active';
+
+-- name: ReleaseBidHold :one
+UPDATE bid_holds
+SET status = 'released', released_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING id, user_id, product_id, bid_id, amount, currency, status, created_at, released_at;
+
+-- name: ListReleasableBidHolds :many
+SELECT bid_holds.id, bid_holds.user_id, bid_holds.product_id, bid_holds.bid_id, bid_holds.amount, bid_holds.currency, bid_holds.status, bid_holds.created_at, bid_holds.released_at
+FROM bid_holds
+JOIN products ON products.id = bid_holds.product_id
+WHERE bid_holds.status = 'active'
+  AND products.finalized_at IS NOT NULL
+  AND (sqlc.narg('product_id')::uuid IS NULL OR bid_holds.product_id = sqlc.narg('product_id'))
+  AND NOT EXISTS (
+    SELECT 1
+    FROM auction_results
+    WHERE auction_results.product_id = bid_holds.product_id AND auction_results.bidder_id = bid_holds.user_id
+  );
@@ -0,0 +1,24 @@
+-- name: CreateBidRetraction :one
+INSERT INTO bid_retractions ("bid_id", "requester_id", "reason")
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetBidRetractionByIdForUpdate :one
+SELECT id, bid_id, requester_id, reason, status, reviewer_id, reviewed_at, created_at
+FROM bid_retractions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListBidRetractionsByProductId :many
+SELECT bid_retractions.id, bid_retractions.bid_id, bid_retractions.reason, bid_retractions.status, bid_retractions.reviewed_at, bid_retractions.created_at, bidder_pseudonyms.number AS bidder_number
+FROM bid_retractions
+JOIN bids ON bids.id = bid_retractions.bid_id
+JOIN bidder_pseudonyms ON bidder_pseudonyms.product_id = bids.product_id AND bidder_pseudonyms.user_id = bids.bidder_id
+WHERE bids.product_id = $1
+ORDER BY bid_retractions.created_at DESC;
+
+-- name: ReviewBidRetraction :one
+UPDATE bid_retractions
+SET status = $2, reviewer_id = $3::uuid, reviewed_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,12 @@
+-- name: AssignBidderPseudonym :one
+INSERT INTO bidder_pseudonyms ("product_id", "user_id", "number")
+SELECT $1, $2, COALESCE(MAX(number), 0) + 1
+FROM bidder_pseudonyms
+WHERE product_id = $1
+ON CONFLICT (product_id, user_id) DO UPDATE SET number = bidder_pseudonyms.number
+RETURNING *;
+
+-- name: GetBidderPseudonymByNumber :one
+SELECT product_id, user_id, number, created_at
+FROM bidder_pseudonyms
+WHERE product_id = $1 AND number = $2;
@@ -1,10 +1,65 @@
 -- name: CreateBid :one
-INSERT INTO bids ("product_id", "bidder_id", "bid_amount")
-VALUES ($1, $2, $3) 
+INSERT INTO bids ("product_id", "bidder_id", "bid_amount", "ip_address", "device_id", "quantity")
+VALUES ($1, $2, $3, $4, $5, $6) 
 RETURNING *;
 
+-- name: GetBidById :one
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity FROM bids WHERE id = $1;
+
 -- name: GetBidsByProductId :many
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids WHERE product_id = $1 ORDER BY bid_amount DESC;
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity FROM bids WHERE product_id = $1 ORDER BY bid_amount DESC;
 
 -- name: GetHighestBidByProductId :one
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids WHERE product_id = $1 ORDER BY bid_amount DESC LIMIT 1;
\ No newline at end of file
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity FROM bids WHERE product_id = $1 AND voided_at IS NULL ORDER BY bid_amount DESC LIMIT 1;
+
+-- name: VoidBid :one
+UPDATE bids SET voided_at = now() WHERE id = $1 AND voided_at IS NULL RETURNING *;
+
+-- name: ListBidsByProductId :many
+SELECT bids.id, bids.product_id, bids.bidder_id, bids.bid_amount, bids.created_at, bids.voided_at, bids.quantity, users.user_name AS bidder_name, bidder_pseudonyms.number AS bidder_number
+FROM bids
+JOIN users ON users.id = bids.bidder_id
+JOIN bidder_pseudonyms ON bidder_pseudonyms.product_id = bids.product_id AND bidder_pseudonyms.user_id = bids.bidder_id
+WHERE bids.product_id = sqlc.arg('product_id')
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR bids.created_at >= sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR bids.created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL OR (bids.created_at, bids.id) < (sqlc.narg('cursor_created_at'), sqlc.narg('cursor_id')::uuid))
+ORDER BY bids.created_at DESC, bids.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListBidSummariesByBidderId :many
+SELECT products.id AS product_id, products.seller_id, products.product_name, products.currency, products.starts_at, products.auction_end,
+       products.base_price, products.quantity, products.pricing, products.finalized_at,
+       mine.my_highest_bid::bigint AS my_highest_bid,
+       mine.last_bid_at::timestamptz AS last_bid_at,
+       EXISTS (
+         SELECT 1 FROM auction_results
+         WHERE auction_results.product_id = products.id AND auction_results.bidder_id = $1
+       ) AS won
+FROM (
+  SELECT product_id, MAX(bid_amount) AS my_highest_bid, MAX(created_at) AS last_bid_at
+  FROM bids
+  WHERE bidder_id = $1 AND voided_at IS NULL
+  GROUP BY product_id
+) mine
+JOIN products ON products.id = mine.product_id
+ORDER BY mine.last_bid_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountBidAuctionsByBidderId :one
+SELECT COUNT(DISTINCT product_id) FROM bids WHERE bidder_id = $1 AND voided_at IS NULL;
+
+-- name: CountBidsByProductId :one
+SELECT COUNT(*) FROM bids WHERE product_id = $1;
+
+-- name: ListStandingBids :many
+SELECT DISTINCT ON (bidder_id) id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity
+FROM bids
+WHERE product_id = $1 AND voided_at IS NULL
+ORDER BY bidder_id, created_at DESC;
+
+-- name: ListStandingBidsByProductIds :many
+SELECT DISTINCT ON (product_id, bidder_id) id, product_id, bidder_id, bid_amount, created_at, voided_at, ip_address, device_id, quantity
+FROM bids
+WHERE product_id = ANY(sqlc.arg('product_ids')::uuid[]) AND voided_at IS NULL
+ORDER BY product_id, bidder_id, created_at DESC;
@@ -0,0 +1,54 @@
+-- name: CreateCategory :one
+INSERT INTO categories ("parent_id", "name")
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetCategoryById :one
+SELECT * FROM categories
+WHERE id = $1;
+
+-- name: ListCategories :many
+WITH RECURSIVE subtree AS (
+  SELECT categories.id AS root_id, categories.id
+  FROM categories
+  UNION ALL
+  SELECT subtree.root_id, categories.id
+  FROM categories
+  JOIN subtree ON categories.parent_id = subtree.id
+)
+SELECT categories.id, categories.parent_id, categories.name, categories.created_at, categories.updated_at,
+       COUNT(products.id) AS live_count
+FROM categories
+JOIN subtree ON subtree.root_id = categories.id
+LEFT JOIN products ON products.category_id = subtree.id
+  AND products.published_at IS NOT NULL AND products.starts_at <= now() AND products.auction_end > now()
+GROUP BY categories.id
+ORDER BY categories.name;
+
+-- name: ListCategorySubtreeIds :many
+WITH RECURSIVE subtree AS (
+  SELECT categories.id FROM categories WHERE categories.id = $1
+  UNION ALL
+  SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+)
+SELECT subtree.id FROM subtree;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET parent_id = $2, name = $3, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ReparentCategoryChildren :exec
+UPDATE categories
+SET parent_id = sqlc.narg('new_parent_id'), updated_at = now()
+WHERE parent_id = sqlc.arg('id')::uuid;
+
+-- name: ReassignCategoryProducts :exec
+UPDATE products
+SET category_id = sqlc.narg('new_category_id'), updated_at = now()
+WHERE category_id = sqlc.arg('category_id')::uuid;
+
+-- name: DeleteCategory :exec
+DELETE FROM categories
+WHERE id = $1;
@@ -0,0 +1,13 @@
+
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications ("user_id", "expires_at")
+VALUES ($1, $2)
+RETURNING id, user_id, expires_at, used_at, created_at;
+
+-- name: UseEmailVerification :one
+UPDATE email_verifications SET used_at = now()
+WHERE id = $1 AND used_at IS NULL AND expires_at > now()
+RETURNING user_id;
+
+-- name: DeleteStaleEmailVerifications :exec
+DELETE FROM email_verifications WHERE user_id = $1 AND (used_at IS NOT NULL OR expires_at <= now());
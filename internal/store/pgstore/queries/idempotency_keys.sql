@@ -0,0 +1,23 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys ("user_id", "scope", "key", "request_hash", "expires_at")
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, scope, key) DO UPDATE
+SET status_code = 0, response = 'null', request_hash = EXCLUDED.request_hash, created_at = now(), expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at < now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT user_id, scope, key, status_code, response, created_at, expires_at, request_hash
+FROM idempotency_keys
+WHERE user_id = $1 AND scope = $2 AND key = $3;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $4, response = $5, expires_at = $6
+WHERE user_id = $1 AND scope = $2 AND key = $3;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE user_id = $1 AND scope = $2 AND key = $3;
+
+-- name: DeleteExpiredIdempotencyKeys :exec
+DELETE FROM idempotency_keys WHERE expires_at < now();
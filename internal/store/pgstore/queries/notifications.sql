@@ -0,0 +1,22 @@
+-- name: CreateNotification :one
+INSERT INTO notifications ("user_id", "product_id", "kind", "subject", "body")
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, product_id, kind, subject, body, read_at, created_at;
+
+-- name: ListNotificationsByUserId :many
+SELECT id, user_id, product_id, kind, subject, body, read_at, created_at
+FROM notifications
+WHERE user_id = sqlc.arg('user_id') AND (NOT sqlc.arg('unread_only')::bool OR read_at IS NULL)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, product_id, kind, subject, body, read_at, created_at;
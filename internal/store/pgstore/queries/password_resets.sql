@@ -0,0 +1,15 @@
+
+-- name: CreatePasswordReset :exec
+INSERT INTO password_resets ("user_id", "token_hash", "expires_at")
+VALUES ($1, $2, $3);
+
+-- name: UsePasswordReset :one
+UPDATE password_resets SET used_at = now()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+RETURNING user_id;
+
+-- name: InvalidatePasswordResets :exec
+UPDATE password_resets SET used_at = now() WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: DeleteStalePasswordResets :exec
+DELETE FROM password_resets WHERE user_id = $1 AND (used_at IS NOT NULL OR expires_at <= now());
@@ -0,0 +1,43 @@
+-- name: CreateProductImage :one
+INSERT INTO product_images ("id", "product_id", "position", "content_type", "width", "height", "size_bytes", "blob_key", "thumbnail_key")
+VALUES (
+  sqlc.arg('id'),
+  sqlc.arg('product_id'),
+  (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = sqlc.arg('product_id')),
+  sqlc.arg('content_type'),
+  sqlc.arg('width'),
+  sqlc.arg('height'),
+  sqlc.arg('size_bytes'),
+  sqlc.arg('blob_key'),
+  sqlc.arg('thumbnail_key')
+)
+RETURNING *;
+
+-- name: GetProductImage :one
+SELECT * FROM product_images
+WHERE id = $1 AND product_id = $2;
+
+-- name: ListProductImages :many
+SELECT * FROM product_images
+WHERE product_id = ANY(sqlc.arg('product_ids')::uuid[])
+ORDER BY product_id, position, created_at;
+
+-- name: SetProductImagePosition :exec
+UPDATE product_images
+SET position = $2
+WHERE id = $1;
+
+-- name: DeleteProductImage :exec
+DELETE FROM product_images
+WHERE id = $1;
+
+-- name: CopyProductImages :exec
+INSERT INTO product_images ("id", "product_id", "position", "content_type", "width", "height", "size_bytes", "blob_key", "thumbnail_key")
+SELECT gen_random_uuid(), sqlc.arg('target_id'), position, content_type, width, height, size_bytes, blob_key, thumbnail_key
+FROM product_images
+WHERE product_id = sqlc.arg('product_id');
+
+-- name: ListReferencedBlobKeys :many
+SELECT blob_key
+FROM product_images
+WHERE blob_key = ANY(sqlc.arg('blob_keys')::text[]);
@@ -0,0 +1,19 @@
+-- name: AddProductTags :exec
+INSERT INTO product_tags ("product_id", "tag")
+SELECT sqlc.arg('product_id'), unnest(sqlc.arg('tags')::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListProductTags :many
+SELECT * FROM product_tags
+WHERE product_id = ANY(sqlc.arg('product_ids')::uuid[])
+ORDER BY product_id, tag;
+
+-- name: DeleteProductTags :exec
+DELETE FROM product_tags
+WHERE product_id = $1;
+
+-- name: CopyProductTags :exec
+INSERT INTO product_tags ("product_id", "tag")
+SELECT sqlc.arg('target_id'), tag
+FROM product_tags
+WHERE product_id = sqlc.arg('product_id');
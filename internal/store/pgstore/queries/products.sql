@@ -1,9 +1,309 @@
 -- name: CreateProduct :one
-INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end")
-VALUES ($1, $2, $3, $4, $5) 
-RETURNING id;
+INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end", "currency", "category_id", "quantity", "pricing", "starts_at", "reserve_price")
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
+RETURNING *;
 
 -- name: GetProductById :one
-SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at 
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent 
 FROM products 
 WHERE id = $1;
+
+-- name: GetProductByIdForUpdate :one
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent 
+FROM products 
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListProducts :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.published_at IS NOT NULL
+  AND (sqlc.arg('status')::text = 'all'
+  OR (sqlc.arg('status') = 'live' AND products.starts_at <= now() AND products.auction_end > now())
+  OR (sqlc.arg('status') = 'ended' AND products.auction_end <= now())
+  OR (sqlc.arg('status') = 'upcoming' AND products.starts_at > now()))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR products.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT categories.id FROM categories WHERE categories.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+    SELECT 1 FROM product_tags WHERE product_tags.product_id = products.id AND product_tags.tag = sqlc.narg('tag')
+  ))
+ORDER BY
+  CASE WHEN sqlc.arg('sort')::text = 'ending_soon' THEN products.auction_end END ASC,
+  CASE WHEN sqlc.arg('sort') = 'newest' THEN products.created_at END DESC,
+  CASE WHEN sqlc.arg('sort') = 'price_asc' THEN COALESCE(book.clearing_price, products.base_price) END ASC,
+  CASE WHEN sqlc.arg('sort') = 'price_desc' THEN COALESCE(book.clearing_price, products.base_price) END DESC,
+  products.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountProducts :one
+SELECT COUNT(*)
+FROM products
+WHERE products.published_at IS NOT NULL
+  AND (sqlc.arg('status')::text = 'all'
+  OR (sqlc.arg('status') = 'live' AND products.starts_at <= now() AND products.auction_end > now())
+  OR (sqlc.arg('status') = 'ended' AND products.auction_end <= now())
+  OR (sqlc.arg('status') = 'upcoming' AND products.starts_at > now()))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR products.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT categories.id FROM categories WHERE categories.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+    SELECT 1 FROM product_tags WHERE product_tags.product_id = products.id AND product_tags.tag = sqlc.narg('tag')
+  ));
+
+-- name: GetProductWithStatsById :one
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.id = $1;
+
+-- name: UpdateProduct :one
+UPDATE products
+SET product_name = $2, description = $3, base_price = $4, currency = $5, auction_end = $6, starts_at = $7, category_id = $8, quantity = $9, pricing = $10, reserve_price = $11, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent;
+
+-- name: DeleteProduct :exec
+DELETE FROM products
+WHERE id = $1;
+
+
+-- name: ListProductsWithStatsByIds :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.id = ANY($1::uuid[]);
+
+-- name: SearchProducts :many
+SELECT products.id,
+       ts_rank(products.search_vector, search.query)::real AS rank,
+       ts_headline(sqlc.arg('language')::text::regconfig, products.product_name, search.query, format('StartSel="%s", StopSel="%s", HighlightAll=true', chr(57344), chr(57345)))::text AS name_snippet,
+       ts_headline(sqlc.arg('language')::text::regconfig, products.description, search.query, format('StartSel="%s", StopSel="%s", MinWords=10, MaxWords=30', chr(57344), chr(57345)))::text AS description_snippet
+FROM products
+CROSS JOIN (SELECT to_tsquery(sqlc.arg('language')::text::regconfig, sqlc.arg('query')::text) AS query) search
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.search_vector @@ search.query
+  AND products.published_at IS NOT NULL
+  AND (sqlc.narg('min_price')::bigint IS NULL OR COALESCE(book.clearing_price, products.base_price) >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price')::bigint IS NULL OR COALESCE(book.clearing_price, products.base_price) <= sqlc.narg('max_price'))
+  AND (sqlc.narg('currency')::text IS NULL OR products.currency = sqlc.narg('currency'))
+  AND (sqlc.arg('status')::text = 'all'
+    OR (sqlc.arg('status') = 'live' AND products.starts_at <= now() AND products.auction_end > now())
+    OR (sqlc.arg('status') = 'ended' AND products.auction_end <= now())
+    OR (sqlc.arg('status') = 'upcoming' AND products.starts_at > now()))
+  AND (sqlc.narg('ending_before')::timestamptz IS NULL OR products.auction_end < sqlc.narg('ending_before'))
+ORDER BY rank DESC, products.auction_end
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: FinalizeProduct :one
+UPDATE products
+SET finalized_at = now(), is_sold = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent;
+
+-- name: ListUnfinalizedEndedProductIds :many
+SELECT id
+FROM products
+WHERE finalized_at IS NULL AND published_at IS NOT NULL AND auction_end <= now()
+ORDER BY auction_end
+LIMIT $1;
+
+-- name: ListWatchedProducts :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+JOIN watchlist ON watchlist.product_id = products.id
+WHERE watchlist.user_id = sqlc.arg('user_id')
+ORDER BY products.auction_end, products.id;
+
+-- name: PublishProduct :one
+UPDATE products
+SET published_at = now(), starts_at = $2, updated_at = now()
+WHERE id = $1 AND published_at IS NULL
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent;
+
+-- name: ListStartedProducts :many
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent
+FROM products
+WHERE published_at IS NOT NULL AND starts_at <= now() AND auction_end > now()
+ORDER BY auction_end;
+
+-- name: ListDraftsBySellerId :many
+SELECT products.id, products.seller_id, products.product_name, products.description, products.base_price, products.auction_end, products.is_sold, products.created_at, products.updated_at, products.currency, products.starts_at, products.category_id, products.quantity, products.pricing, products.published_at, products.reserve_price, products.relisted_from_id, products.auto_relist_remaining, products.auto_relist_drop_percent, products.bid_hold_percent,
+       COALESCE(book.clearing_price, products.base_price)::bigint AS current_price,
+       stats.bid_count::int AS bid_count,
+       (SELECT COUNT(*) FROM watchlist WHERE watchlist.product_id = products.id)::int AS watch_count
+FROM products
+JOIN LATERAL (
+  SELECT COUNT(*) AS bid_count
+  FROM bids
+  WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+) stats ON true
+JOIN LATERAL (
+  SELECT MIN(standing.bid_amount) AS clearing_price
+  FROM (
+    SELECT latest.bid_amount,
+           COALESCE(SUM(latest.quantity) OVER (ORDER BY latest.bid_amount DESC, latest.created_at ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS units_before
+    FROM (
+      SELECT DISTINCT ON (bids.bidder_id) bids.bid_amount, bids.quantity, bids.created_at
+      FROM bids
+      WHERE bids.product_id = products.id AND bids.voided_at IS NULL
+      ORDER BY bids.bidder_id, bids.created_at DESC
+    ) latest
+  ) standing
+  WHERE standing.units_before < products.quantity
+) book ON true
+WHERE products.seller_id = $1 AND products.published_at IS NULL
+ORDER BY products.updated_at DESC, products.id;
+
+-- name: CreateRelistedProduct :one
+INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end", "currency", "category_id", "quantity", "pricing", "starts_at", "reserve_price", "relisted_from_id", "auto_relist_remaining", "auto_relist_drop_percent", "bid_hold_percent", "published_at")
+SELECT seller_id, product_name, description, sqlc.arg('base_price'), sqlc.arg('auction_end'), currency, category_id, quantity, pricing, now(), reserve_price, id, sqlc.arg('auto_relist_remaining'), auto_relist_drop_percent, bid_hold_percent, now()
+FROM products
+WHERE id = sqlc.arg('id')
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent;
+
+-- name: GetRelistOf :one
+SELECT id
+FROM products
+WHERE relisted_from_id = $1;
+
+-- name: SetProductRelistPolicy :one
+UPDATE products
+SET auto_relist_remaining = $2, auto_relist_drop_percent = $3, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent;
+
+-- name: ListRelistChain :many
+WITH RECURSIVE ancestors AS (
+  SELECT products.id, products.relisted_from_id FROM products WHERE products.id = $1
+  UNION ALL
+  SELECT products.id, products.relisted_from_id FROM products JOIN ancestors ON products.id = ancestors.relisted_from_id
+), chain AS (
+  SELECT ancestors.id FROM ancestors WHERE ancestors.relisted_from_id IS NULL
+  UNION ALL
+  SELECT products.id FROM products JOIN chain ON products.relisted_from_id = chain.id
+)
+SELECT products.id, products.base_price, products.currency, products.starts_at, products.auction_end, products.is_sold, products.finalized_at, products.relisted_from_id
+FROM products
+JOIN chain ON chain.id = products.id
+ORDER BY products.created_at, products.id;
+
+-- name: SetProductBidHold :one
+UPDATE products
+SET bid_hold_percent = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, currency, starts_at, search_vector, category_id, quantity, pricing, finalized_at, published_at, reserve_price, relisted_from_id, auto_relist_remaining, auto_relist_drop_percent, bid_hold_percent;
@@ -0,0 +1,17 @@
+-- name: CreateRoomMessage :one
+INSERT INTO room_messages ("product_id", "sender_id", "content")
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetRecentRoomMessagesByProductId :many
+SELECT id, product_id, sender_id, content, deleted_at, deleted_by, created_at
+FROM room_messages
+WHERE product_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: DeleteRoomMessage :one
+UPDATE room_messages
+SET deleted_at = now(), deleted_by = $3::uuid
+WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL
+RETURNING *;
@@ -0,0 +1,60 @@
+-- name: UpsertShillFlag :exec
+INSERT INTO shill_flags ("bidder_id", "seller_id", "rule", "score", "details")
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (bidder_id, seller_id, rule) DO UPDATE
+SET score = EXCLUDED.score, details = EXCLUDED.details, updated_at = now()
+WHERE shill_flags.status = 'pending';
+
+-- name: ListShillFlagsByStatus :many
+SELECT id, bidder_id, seller_id, rule, score, details, status, reviewer_id, reviewed_at, created_at, updated_at
+FROM shill_flags
+WHERE status = $1
+ORDER BY score DESC, created_at;
+
+-- name: GetShillFlagById :one
+SELECT id, bidder_id, seller_id, rule, score, details, status, reviewer_id, reviewed_at, created_at, updated_at
+FROM shill_flags
+WHERE id = $1;
+
+-- name: ReviewShillFlag :one
+UPDATE shill_flags
+SET status = $2, reviewer_id = $3::uuid, reviewed_at = now(), updated_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: ListNewAccountSellerConcentration :many
+SELECT bids.bidder_id, products.seller_id,
+       COUNT(*)::int AS seller_bids,
+       COUNT(DISTINCT bids.product_id)::int AS seller_auctions,
+       (SELECT COUNT(*) FROM bids all_bids WHERE all_bids.bidder_id = bids.bidder_id)::int AS total_bids
+FROM bids
+JOIN products ON products.id = bids.product_id
+JOIN users ON users.id = bids.bidder_id
+WHERE users.created_at > now() - make_interval(days => sqlc.arg('new_account_days')::int)
+  AND bids.bidder_id <> products.seller_id
+GROUP BY bids.bidder_id, products.seller_id
+HAVING COUNT(*) >= sqlc.arg('min_bids')::int;
+
+-- name: ListNeverWinningBidders :many
+WITH participation AS (
+  SELECT DISTINCT bids.bidder_id, products.id AS product_id, products.seller_id
+  FROM bids
+  JOIN products ON products.id = bids.product_id
+  WHERE products.finalized_at IS NOT NULL AND bids.voided_at IS NULL AND bids.bidder_id <> products.seller_id
+)
+SELECT participation.bidder_id, participation.seller_id, COUNT(*)::int AS auctions
+FROM participation
+LEFT JOIN auction_results ON auction_results.product_id = participation.product_id AND auction_results.bidder_id = participation.bidder_id
+GROUP BY participation.bidder_id, participation.seller_id
+HAVING COUNT(*) >= sqlc.arg('min_auctions')::int AND bool_and(auction_results.id IS NULL);
+
+-- name: ListSellerOriginMatches :many
+SELECT bids.bidder_id, products.seller_id,
+       COUNT(DISTINCT bids.id) FILTER (WHERE bids.ip_address = seller_origins.ip_address)::int AS same_ip_bids,
+       COUNT(DISTINCT bids.id) FILTER (WHERE bids.device_id <> '' AND bids.device_id = seller_origins.device_id)::int AS same_device_bids
+FROM bids
+JOIN products ON products.id = bids.product_id
+JOIN user_origins seller_origins ON seller_origins.user_id = products.seller_id
+WHERE bids.bidder_id <> products.seller_id
+  AND (bids.ip_address = seller_origins.ip_address OR (bids.device_id <> '' AND bids.device_id = seller_origins.device_id))
+GROUP BY bids.bidder_id, products.seller_id;
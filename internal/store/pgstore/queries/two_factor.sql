@@ -0,0 +1,44 @@
+
+-- name: StartUserTotpEnrollment :execrows
+INSERT INTO user_totp ("user_id", "secret")
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_step = NULL, created_at = now()
+WHERE user_totp.enabled_at IS NULL;
+
+-- name: GetUserTotp :one
+SELECT user_id, secret, enabled_at, last_step, created_at, failed_attempts, locked_until FROM user_totp WHERE user_id = $1;
+
+-- name: EnableUserTotp :execrows
+UPDATE user_totp SET enabled_at = now(), last_step = $2 WHERE user_id = $1 AND enabled_at IS NULL;
+
+-- name: UseUserTotpStep :execrows
+UPDATE user_totp SET last_step = $2
+WHERE user_id = $1 AND enabled_at IS NOT NULL AND (last_step IS NULL OR last_step < $2);
+
+-- name: DeleteUserTotp :exec
+DELETE FROM user_totp WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes ("user_id", "code_hash")
+VALUES ($1, $2);
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE user_id = $1;
+
+-- name: RecordTwoFactorFailure :one
+UPDATE user_totp
+SET failed_attempts = CASE WHEN failed_attempts + 1 >= sqlc.arg('max_failures')::int THEN 0 ELSE failed_attempts + 1 END,
+    locked_until = CASE WHEN failed_attempts + 1 >= sqlc.arg('max_failures')::int THEN sqlc.arg('locked_until')::timestamptz ELSE locked_until END
+WHERE user_id = sqlc.arg('user_id')
+RETURNING locked_until;
+
+-- name: ResetTwoFactorFailures :exec
+UPDATE user_totp SET failed_attempts = 0, locked_until = NULL
+WHERE user_id = $1 AND (failed_attempts > 0 OR locked_until IS NOT NULL);
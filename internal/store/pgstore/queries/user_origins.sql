@@ -0,0 +1,4 @@
+-- name: RecordUserOrigin :exec
+INSERT INTO user_origins ("user_id", "ip_address", "device_id")
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, ip_address, device_id) DO UPDATE SET last_seen_at = now();
@@ -4,7 +4,36 @@ VALUES ($1, $2, $3, $4)
 RETURNING id;
 
 -- name: GetUserById :one
-SELECT id, user_name, email, password_hash, bio, created_at, updated_at FROM users WHERE id = $1;
+SELECT id, user_name, email, password_hash, bio, created_at, updated_at, is_admin, retraction_count, email_verified_at, session_generation FROM users WHERE id = $1;
 
 -- name: GetUserByEmail :one
-SELECT id, user_name, email, password_hash, bio, created_at, updated_at FROM users WHERE email = $1;
\ No newline at end of file
+SELECT id, user_name, email, password_hash, bio, created_at, updated_at, is_admin, retraction_count, email_verified_at, session_generation FROM users WHERE email = $1;
+
+-- name: IncrementUserRetractionCount :exec
+UPDATE users SET retraction_count = retraction_count + 1, updated_at = now() WHERE id = $1;
+
+-- name: UpdateUserProfile :one
+UPDATE users SET user_name = $2, bio = $3, updated_at = now() WHERE id = $1
+RETURNING id, user_name, email, password_hash, bio, created_at, updated_at, is_admin, retraction_count, email_verified_at, session_generation;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2, updated_at = now() WHERE id = $1;
+
+-- name: GetSellerStats :one
+SELECT COUNT(*) FILTER (WHERE products.published_at IS NOT NULL)::int AS listed_count,
+       COUNT(*) FILTER (WHERE products.published_at IS NOT NULL AND products.starts_at <= now() AND products.auction_end > now())::int AS live_count,
+       COUNT(*) FILTER (WHERE products.is_sold)::int AS sold_count,
+       COUNT(*) FILTER (WHERE products.finalized_at IS NOT NULL AND NOT products.is_sold)::int AS unsold_count,
+       COALESCE((SELECT SUM(auction_results.quantity) FROM auction_results JOIN products ON products.id = auction_results.product_id WHERE products.seller_id = $1), 0)::int AS units_sold
+FROM products
+WHERE products.seller_id = $1;
+
+-- name: GetUserSessionGeneration :one
+SELECT session_generation FROM users WHERE id = $1;
+
+-- name: BumpUserSessionGeneration :one
+UPDATE users SET session_generation = session_generation + 1, updated_at = now() WHERE id = $1
+RETURNING session_generation;
+
+-- name: MarkUserEmailVerified :exec
+UPDATE users SET email_verified_at = now(), updated_at = now() WHERE id = $1 AND email_verified_at IS NULL;
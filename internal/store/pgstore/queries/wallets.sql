@@ -0,0 +1,41 @@
+-- name: CreditWallet :one
+INSERT INTO wallets ("user_id", "currency", "balance")
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, currency) DO UPDATE
+SET balance = wallets.balance + EXCLUDED.balance, updated_at = now()
+RETURNING user_id, currency, balance, held, created_at, updated_at;
+
+-- name: DebitWallet :one
+UPDATE wallets
+SET balance = balance - $3, updated_at = now()
+WHERE user_id = $1 AND currency = $2 AND balance - held >= $3
+RETURNING user_id, currency, balance, held, created_at, updated_at;
+
+-- name: HoldWalletFunds :one
+UPDATE wallets
+SET held = held + $3, updated_at = now()
+WHERE user_id = $1 AND currency = $2 AND balance - held >= $3
+RETURNING user_id, currency, balance, held, created_at, updated_at;
+
+-- name: ReleaseWalletFunds :exec
+UPDATE wallets
+SET held = held - $3, updated_at = now()
+WHERE user_id = $1 AND currency = $2;
+
+-- name: ListWalletsByUserId :many
+SELECT user_id, currency, balance, held, created_at, updated_at
+FROM wallets
+WHERE user_id = $1
+ORDER BY currency;
+
+-- name: CreateWalletTransaction :one
+INSERT INTO wallet_transactions ("user_id", "currency", "kind", "amount")
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, currency, kind, amount, created_at;
+
+-- name: ListWalletTransactionsByUserId :many
+SELECT id, user_id, currency, kind, amount, created_at
+FROM wallet_transactions
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
@@ -0,0 +1,40 @@
+-- name: AddToWatchlist :exec
+INSERT INTO watchlist ("user_id", "product_id")
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveFromWatchlist :execrows
+DELETE FROM watchlist
+WHERE user_id = $1 AND product_id = $2;
+
+-- name: ListDueEndingSoonWatches :many
+SELECT watchlist.user_id, watchlist.product_id, products.product_name, products.auction_end, users.email, users.user_name
+FROM watchlist
+JOIN products ON products.id = watchlist.product_id
+JOIN users ON users.id = watchlist.user_id
+WHERE watchlist.ending_soon_sent_at IS NULL
+  AND products.starts_at <= now()
+  AND products.auction_end > now()
+  AND products.auction_end <= sqlc.arg('ending_before')
+ORDER BY products.auction_end
+LIMIT sqlc.arg('limit');
+
+-- name: MarkWatchEndingSoonSent :exec
+UPDATE watchlist
+SET ending_soon_sent_at = now()
+WHERE user_id = $1 AND product_id = $2;
+
+-- name: ListDueEndedWatches :many
+SELECT watchlist.user_id, watchlist.product_id, products.product_name, products.auction_end, users.email, users.user_name, products.is_sold
+FROM watchlist
+JOIN products ON products.id = watchlist.product_id
+JOIN users ON users.id = watchlist.user_id
+WHERE watchlist.ended_sent_at IS NULL
+  AND products.finalized_at IS NOT NULL
+ORDER BY products.auction_end
+LIMIT $1;
+
+-- name: MarkWatchEndedSent :exec
+UPDATE watchlist
+SET ended_sent_at = now()
+WHERE user_id = $1 AND product_id = $2;
@@ -11,6 +11,18 @@ import (
 	"github.com/google/uuid"
 )
 
+const bumpUserSessionGeneration = `-- name: BumpUserSessionGeneration :one
+UPDATE users SET session_generation = session_generation + 1, updated_at = now() WHERE id = $1
+RETURNING session_generation
+`
+
+func (q *Queries) BumpUserSessionGeneration(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, bumpUserSessionGeneration, id)
+	var sessionGeneration int32
+	err := row.Scan(&sessionGeneration)
+	return sessionGeneration, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users ("user_name", "email", "password_hash", "bio") 
 VALUES ($1, $2, $3, $4) 
@@ -36,8 +48,39 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (uuid.UU
 	return id, err
 }
 
+const getSellerStats = `-- name: GetSellerStats :one
+SELECT COUNT(*) FILTER (WHERE products.published_at IS NOT NULL)::int AS listed_count,
+       COUNT(*) FILTER (WHERE products.published_at IS NOT NULL AND products.starts_at <= now() AND products.auction_end > now())::int AS live_count,
+       COUNT(*) FILTER (WHERE products.is_sold)::int AS sold_count,
+       COUNT(*) FILTER (WHERE products.finalized_at IS NOT NULL AND NOT products.is_sold)::int AS unsold_count,
+       COALESCE((SELECT SUM(auction_results.quantity) FROM auction_results JOIN products ON products.id = auction_results.product_id WHERE products.seller_id = $1), 0)::int AS units_sold
+FROM products
+WHERE products.seller_id = $1
+`
+
+type GetSellerStatsRow struct {
+	ListedCount int32 `json:"listed_count"`
+	LiveCount   int32 `json:"live_count"`
+	SoldCount   int32 `json:"sold_count"`
+	UnsoldCount int32 `json:"unsold_count"`
+	UnitsSold   int32 `json:"units_sold"`
+}
+
+func (q *Queries) GetSellerStats(ctx context.Context, sellerID uuid.UUID) (GetSellerStatsRow, error) {
+	row := q.db.QueryRow(ctx, getSellerStats, sellerID)
+	var i GetSellerStatsRow
+	err := row.Scan(
+		&i.ListedCount,
+		&i.LiveCount,
+		&i.SoldCount,
+		&i.UnsoldCount,
+		&i.UnitsSold,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, user_name, email, password_hash, bio, created_at, updated_at FROM users WHERE email = $1
+SELECT id, user_name, email, password_hash, bio, created_at, updated_at, is_admin, retraction_count, email_verified_at, session_generation FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -51,12 +94,16 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.RetractionCount,
+		&i.EmailVerifiedAt,
+		&i.SessionGeneration,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, user_name, email, password_hash, bio, created_at, updated_at FROM users WHERE id = $1
+SELECT id, user_name, email, password_hash, bio, created_at, updated_at, is_admin, retraction_count, email_verified_at, session_generation FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
@@ -70,6 +117,83 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.RetractionCount,
+		&i.EmailVerifiedAt,
+		&i.SessionGeneration,
+	)
+	return i, err
+}
+
+const getUserSessionGeneration = `-- name: GetUserSessionGeneration :one
+SELECT session_generation FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserSessionGeneration(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, getUserSessionGeneration, id)
+	var sessionGeneration int32
+	err := row.Scan(&sessionGeneration)
+	return sessionGeneration, err
+}
+
+const incrementUserRetractionCount = `-- name: IncrementUserRetractionCount :exec
+UPDATE users SET retraction_count = retraction_count + 1, updated_at = now() WHERE id = $1
+`
+
+func (q *Queries) IncrementUserRetractionCount(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, incrementUserRetractionCount, id)
+	return err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :exec
+UPDATE users SET email_verified_at = now(), updated_at = now() WHERE id = $1 AND email_verified_at IS NULL
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markUserEmailVerified, id)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password_hash = $2, updated_at = now() WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           uuid.UUID `json:"id"`
+	PasswordHash []byte    `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users SET user_name = $2, bio = $3, updated_at = now() WHERE id = $1
+RETURNING id, user_name, email, password_hash, bio, created_at, updated_at, is_admin, retraction_count, email_verified_at, session_generation
+`
+
+type UpdateUserProfileParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserName string    `json:"user_name"`
+	Bio      string    `json:"bio"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.ID, arg.UserName, arg.Bio)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsAdmin,
+		&i.RetractionCount,
+		&i.EmailVerifiedAt,
+		&i.SessionGeneration,
 	)
 	return i, err
 }
@@ -0,0 +1,31 @@
+package bid
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+type PlaceBidReq struct {
+	Amount   json.Number `json:"amount"`
+	Quantity int32       `json:"quantity"`
+}
+
+// Units returns how many units the bid asks for, one when it did not say.
+func (req PlaceBidReq) Units() int32 {
+	if req.Quantity == 0 {
+		return 1
+	}
+
+	return req.Quantity
+}
+
+func (req PlaceBidReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Amount.String()), "amount", "this field cannot be blank")
+	eval.CheckField(req.Quantity >= 0, "quantity", "this field cannot be negative")
+
+	return eval
+}
@@ -0,0 +1,20 @@
+package bid
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+type RequestRetractionReq struct {
+	Reason string `json:"reason"`
+}
+
+func (req RequestRetractionReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Reason), "reason", "this field cannot be blank")
+	eval.CheckField((validator.MinChars(req.Reason, 10) && validator.MaxChars(req.Reason, 255)), "reason", "this field must have a length between 10 and 255 characters")
+
+	return eval
+}
@@ -0,0 +1,24 @@
+package category
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// SaveCategoryReq is the body used both to create and to replace a category.
+// A zero ParentID places the category at the top level.
+type SaveCategoryReq struct {
+	Name     string    `json:"name"`
+	ParentID uuid.UUID `json:"parent_id"`
+}
+
+func (req SaveCategoryReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Name, 100), "name", "this field must have at most 100 characters")
+
+	return eval
+}
@@ -0,0 +1,24 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+type SendChatMessageReq struct {
+	Content      string   `json:"message"`
+	BlockedWords []string `json:"-"`
+}
+
+const maxChatMessageLength = 280
+
+func (req SendChatMessageReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Content), "message", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Content, maxChatMessageLength), "message", "this field must have at most 280 characters")
+	eval.CheckField(validator.NotContainsAny(req.Content, req.BlockedWords), "message", "this message contains blocked words")
+
+	return eval
+}
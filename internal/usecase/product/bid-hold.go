@@ -0,0 +1,21 @@
+package product
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// BidHoldReq sets how many percent of each bid are held in the bidder's
+// wallet while the bid stands. Zero places no hold.
+type BidHoldReq struct {
+	Percent int32 `json:"percent"`
+}
+
+func (req BidHoldReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(req.Percent >= 0 && req.Percent <= 100, "percent", "must be between 0 and 100")
+
+	return eval
+}
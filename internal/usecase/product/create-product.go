@@ -2,31 +2,88 @@ package product
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
 	"github.com/gregoryAlvim/gobid/internal/validator"
 )
 
+// CreateProductReq creates a draft. Only the shape of the fields is checked
+// here, so a draft can be saved half done; the rules of Listing are enforced
+// when it is published. A zero StartsAt starts the auction on publish and
+// an empty ReservePrice lists the product without a reserve.
 type CreateProductReq struct {
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	ProductName  string      `json:"product_name"`
+	Description  string      `json:"description"`
+	BasePrice    json.Number `json:"base_price"`
+	Currency     string      `json:"currency"`
+	ReservePrice json.Number `json:"reserve_price"`
+	StartsAt     time.Time   `json:"starts_at"`
+	AuctionEnd   time.Time   `json:"auction_end"`
+	CategoryID   uuid.UUID   `json:"category_id"`
+	Tags         []string    `json:"tags"`
+	Quantity     int32       `json:"quantity"`
+	Pricing      string      `json:"pricing"`
 }
 
-const minAuctionDuration = 2 * time.Hour
-
 func (req CreateProductReq) Valid(ctx context.Context) validator.Evaluator {
 	var eval validator.Evaluator
 
 	eval.CheckField(validator.NotBlank(req.ProductName), "product_name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Description, maxDescription), "description", "this field must have at most 255 characters")
+
+	eval.CheckField(money.IsSupportedCurrency(req.Currency), "currency", "must be a supported ISO 4217 currency code")
+	checkBasePrice(&eval, req.BasePrice, req.Currency)
+
+	if req.ReservePrice != "" {
+		checkReservePrice(&eval, req.ReservePrice, req.Currency)
+	}
+
+	eval.CheckField(!req.AuctionEnd.IsZero(), "auction_end", "this field cannot be blank")
+	eval.CheckField(req.StartsAt.IsZero() || req.StartsAt.Before(req.AuctionEnd), "starts_at", "must be before auction_end")
 
-	eval.CheckField(validator.NotBlank(req.Description), "description", "this field cannot be blank")
-	eval.CheckField((validator.MinChars(req.Description, 10) && validator.MaxChars(req.Description, 255)), "description", "this field must have a length between 10 and 255 characters")
+	checkTags(&eval, req.Tags)
 
-	eval.CheckField(req.BasePrice > 0, "base_price", "this field cannot be zero")
+	if req.Quantity != 0 {
+		checkQuantity(&eval, req.Quantity)
+	}
 
-	eval.CheckField(req.AuctionEnd.Sub(time.Now().UTC()) >= minAuctionDuration, "auction_end", "must be at least two hours duration")
+	if req.Pricing != "" {
+		checkPricing(&eval, req.Pricing)
+	}
 
 	return eval
 }
+
+// checkBasePrice makes sure the amount can be stored in the currency. A
+// draft may still have a zero price.
+func checkBasePrice(eval *validator.Evaluator, amount json.Number, currency string) {
+	basePrice, err := money.Parse(amount.String(), currency)
+	switch {
+	case errors.Is(err, money.ErrTooManyDecimals):
+		eval.AddFieldError("base_price", "this field has too many decimal places for the currency")
+	case errors.Is(err, money.ErrUnsupportedCurrency):
+	case err != nil:
+		eval.AddFieldError("base_price", "this field must be a valid decimal amount")
+	default:
+		eval.CheckField(basePrice.Amount >= 0, "base_price", "this field cannot be negative")
+	}
+}
+
+// checkReservePrice makes sure the reserve is a positive amount that can be
+// stored in the currency.
+func checkReservePrice(eval *validator.Evaluator, amount json.Number, currency string) {
+	reservePrice, err := money.Parse(amount.String(), currency)
+	switch {
+	case errors.Is(err, money.ErrTooManyDecimals):
+		eval.AddFieldError("reserve_price", "this field has too many decimal places for the currency")
+	case errors.Is(err, money.ErrUnsupportedCurrency):
+	case err != nil:
+		eval.AddFieldError("reserve_price", "this field must be a valid decimal amount")
+	default:
+		eval.CheckField(reservePrice.IsPositive(), "reserve_price", "this field must be positive")
+	}
+}
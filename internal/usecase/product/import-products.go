@@ -0,0 +1,231 @@
+package product
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	MaxImportRows = 1000
+)
+
+var (
+	ErrUnknownImportFormat = errors.New("the import format must be csv or ndjson")
+	ErrTooManyImportRows   = fmt.Errorf("an import can have at most %d rows", MaxImportRows)
+	ErrEmptyImport         = errors.New("the import file has no rows")
+)
+
+// ImportRow is one listing of a bulk import file, as a CreateProductReq.
+// Line is the line of the file it came from. Problems holds the fields that
+// could not even be decoded, in the shape Valid reports them.
+type ImportRow struct {
+	Line     int
+	Req      CreateProductReq
+	Problems map[string]string
+}
+
+// ImportColumns are the columns a CSV import may have, named like the JSON
+// fields of CreateProductReq. Only product_name is required in the header;
+// tags are separated by "|".
+var ImportColumns = []string{
+	"product_name", "description", "base_price", "currency", "reserve_price", "starts_at",
+	"auction_end", "category_id", "tags", "quantity", "pricing",
+}
+
+// DecodeImport reads every row of an import file. A row that cannot be
+// decoded is still returned, with its problems, so that the whole file can
+// be reported on at once; only a malformed file fails as a whole.
+func DecodeImport(r io.Reader, format string) ([]ImportRow, error) {
+	var rows []ImportRow
+	var err error
+
+	switch format {
+	case ImportFormatCSV:
+		rows, err = decodeCSVImport(r)
+	case ImportFormatNDJSON:
+		rows, err = decodeNDJSONImport(r)
+	default:
+		return nil, ErrUnknownImportFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyImport
+	}
+
+	return rows, nil
+}
+
+func decodeNDJSONImport(r io.Reader) ([]ImportRow, error) {
+	var rows []ImportRow
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		if len(rows) == MaxImportRows {
+			return nil, ErrTooManyImportRows
+		}
+
+		row := ImportRow{Line: line}
+
+		if err := json.Unmarshal(data, &row.Req); err != nil {
+			row.Problems = map[string]string{"row": "must be a valid JSON object"}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ndjson import: %w", err)
+	}
+
+	return rows, nil
+}
+
+func decodeCSVImport(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyImport
+		}
+
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(ImportColumns, column) {
+			return nil, fmt.Errorf("unknown csv column %q, columns are %s", column, strings.Join(ImportColumns, ", "))
+		}
+
+		header[i] = column
+	}
+
+	if !slices.Contains(header, "product_name") {
+		return nil, errors.New("the csv header must have a product_name column")
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read csv import: %w", err)
+		}
+
+		if len(rows) == MaxImportRows {
+			return nil, ErrTooManyImportRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := ImportRow{Line: line}
+
+		for i, value := range record {
+			if problem := setImportField(&row.Req, header[i], strings.TrimSpace(value)); problem != "" {
+				if row.Problems == nil {
+					row.Problems = make(map[string]string)
+				}
+
+				row.Problems[header[i]] = problem
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// setImportField sets the field of req named by column from its CSV text,
+// returning what is wrong with the value if it cannot be decoded.
+func setImportField(req *CreateProductReq, column, value string) string {
+	switch column {
+	case "product_name":
+		req.ProductName = value
+	case "description":
+		req.Description = value
+	case "base_price":
+		req.BasePrice = json.Number(value)
+	case "currency":
+		req.Currency = value
+	case "reserve_price":
+		req.ReservePrice = json.Number(value)
+	case "pricing":
+		req.Pricing = value
+	case "tags":
+		if value != "" {
+			req.Tags = strings.Split(value, "|")
+		}
+	case "starts_at", "auction_end":
+		if value == "" {
+			return ""
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return "must be a RFC 3339 timestamp"
+		}
+
+		if column == "starts_at" {
+			req.StartsAt = t
+		} else {
+			req.AuctionEnd = t
+		}
+	case "category_id":
+		if value == "" {
+			return ""
+		}
+
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return "must be a valid uuid"
+		}
+
+		req.CategoryID = id
+	case "quantity":
+		if value == "" {
+			return ""
+		}
+
+		quantity, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return "must be a whole number"
+		}
+
+		req.Quantity = int32(quantity)
+	}
+
+	return ""
+}
@@ -0,0 +1,83 @@
+package product
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+const (
+	minAuctionDuration = 2 * time.Hour
+	maxDescription     = 255
+	maxTags            = 10
+	maxTagLength       = 30
+	maxQuantity        = 10000
+)
+
+// Listing is a product as it would go live. Drafts are only checked for
+// shape while they are edited; Valid holds every rule a product must meet
+// to be published, and it runs again on each publish.
+type Listing struct {
+	ProductName string
+	Description string
+	BasePrice   money.Money
+	// ReservePrice is the lowest unit price the seller accepts, nil when
+	// there is no reserve.
+	ReservePrice *money.Money
+	StartsAt     time.Time
+	AuctionEnd   time.Time
+	Tags         []string
+	Quantity     int32
+	Pricing      string
+}
+
+func (l Listing) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(l.ProductName), "product_name", "this field cannot be blank")
+
+	eval.CheckField(validator.NotBlank(l.Description), "description", "this field cannot be blank")
+	eval.CheckField((validator.MinChars(l.Description, 10) && validator.MaxChars(l.Description, maxDescription)), "description", "this field must have a length between 10 and 255 characters")
+
+	eval.CheckField(money.IsSupportedCurrency(l.BasePrice.Currency), "currency", "must be a supported ISO 4217 currency code")
+	eval.CheckField(l.BasePrice.IsPositive(), "base_price", "this field cannot be zero")
+
+	if l.ReservePrice != nil {
+		eval.CheckField(l.ReservePrice.Currency == l.BasePrice.Currency, "reserve_price", "must be in the same currency as base_price")
+		eval.CheckField(l.ReservePrice.Amount > l.BasePrice.Amount, "reserve_price", "must be higher than base_price")
+	}
+
+	start := time.Now().UTC()
+	if l.StartsAt.After(start) {
+		start = l.StartsAt
+	}
+
+	eval.CheckField(l.AuctionEnd.Sub(start) >= minAuctionDuration, "auction_end", "must be at least two hours duration")
+
+	checkTags(&eval, l.Tags)
+	checkQuantity(&eval, l.Quantity)
+	checkPricing(&eval, l.Pricing)
+
+	return eval
+}
+
+func checkTags(eval *validator.Evaluator, tags []string) {
+	eval.CheckField(len(tags) <= maxTags, "tags", "must have at most 10 tags")
+	for _, tag := range tags {
+		if !validator.NotBlank(tag) || !validator.MaxChars(strings.TrimSpace(tag), maxTagLength) {
+			eval.AddFieldError("tags", "each tag must have between 1 and 30 characters")
+			break
+		}
+	}
+}
+
+func checkQuantity(eval *validator.Evaluator, quantity int32) {
+	eval.CheckField(quantity >= 1 && quantity <= maxQuantity, "quantity", "must be between 1 and 10000 units")
+}
+
+func checkPricing(eval *validator.Evaluator, pricing string) {
+	eval.CheckField(pricing == "uniform" || pricing == "pay_as_bid", "pricing", "must be either uniform or pay_as_bid")
+}
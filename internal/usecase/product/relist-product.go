@@ -0,0 +1,53 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+const (
+	maxAutoRelists       = 10
+	maxRelistDropPercent = 90
+)
+
+// RelistProductReq clones a finished product into a new auction. The base
+// price is in the currency of the product and defaults to the current one;
+// the end defaults to the same duration as the finished auction.
+type RelistProductReq struct {
+	BasePrice  *json.Number `json:"base_price"`
+	AuctionEnd *time.Time   `json:"auction_end"`
+}
+
+func (req RelistProductReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if req.BasePrice != nil {
+		eval.CheckField(validator.NotBlank(req.BasePrice.String()), "base_price", "this field cannot be blank")
+	}
+
+	if req.AuctionEnd != nil {
+		eval.CheckField(req.AuctionEnd.After(time.Now()), "auction_end", "must be in the future")
+	}
+
+	return eval
+}
+
+// RelistPolicyReq sets how many times an unsold auction is relisted on its
+// own, and by how much its base price drops each time. Zero max_relists
+// turns auto-relisting off.
+type RelistPolicyReq struct {
+	MaxRelists       int32 `json:"max_relists"`
+	PriceDropPercent int32 `json:"price_drop_percent"`
+}
+
+func (req RelistPolicyReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(req.MaxRelists >= 0 && req.MaxRelists <= maxAutoRelists, "max_relists", "must be between 0 and 10")
+	eval.CheckField(req.PriceDropPercent >= 0 && req.PriceDropPercent <= maxRelistDropPercent, "price_drop_percent", "must be between 0 and 90")
+
+	return eval
+}
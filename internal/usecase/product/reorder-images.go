@@ -0,0 +1,22 @@
+package product
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// ReorderImagesReq lists every image of a product in the order they should
+// be shown.
+type ReorderImagesReq struct {
+	ImageIDs []uuid.UUID `json:"image_ids"`
+}
+
+func (req ReorderImagesReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(len(req.ImageIDs) > 0, "image_ids", "this field cannot be empty")
+
+	return eval
+}
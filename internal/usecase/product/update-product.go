@@ -0,0 +1,97 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// UpdateProductReq is a partial update: only the fields present in the body
+// are changed. The base and reserve prices must come with their currency,
+// and a zero reserve_price removes the reserve. A zero category_id removes
+// the product from its category. Like CreateProductReq
+// it only checks the shape of the fields; published products are held to
+// the rules of Listing by the service.
+type UpdateProductReq struct {
+	ProductName  *string      `json:"product_name"`
+	Description  *string      `json:"description"`
+	BasePrice    *json.Number `json:"base_price"`
+	Currency     *string      `json:"currency"`
+	ReservePrice *json.Number `json:"reserve_price"`
+	StartsAt     *time.Time   `json:"starts_at"`
+	AuctionEnd   *time.Time   `json:"auction_end"`
+	CategoryID   *uuid.UUID   `json:"category_id"`
+	Tags         *[]string    `json:"tags"`
+	Quantity     *int32       `json:"quantity"`
+	Pricing      *string      `json:"pricing"`
+}
+
+func (req UpdateProductReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if req.ProductName == nil && req.Description == nil && req.BasePrice == nil && req.ReservePrice == nil && req.StartsAt == nil && req.AuctionEnd == nil &&
+		req.CategoryID == nil && req.Tags == nil && req.Quantity == nil && req.Pricing == nil {
+		eval.AddFieldError("product", "at least one field must be changed")
+	}
+
+	if req.ProductName != nil {
+		eval.CheckField(validator.NotBlank(*req.ProductName), "product_name", "this field cannot be blank")
+	}
+
+	if req.Description != nil {
+		eval.CheckField(validator.MaxChars(*req.Description, maxDescription), "description", "this field must have at most 255 characters")
+	}
+
+	if (req.BasePrice == nil && req.ReservePrice == nil) != (req.Currency == nil) {
+		eval.AddFieldError("currency", "currency must be sent along with base_price or reserve_price")
+	}
+
+	if req.Currency != nil {
+		eval.CheckField(money.IsSupportedCurrency(*req.Currency), "currency", "must be a supported ISO 4217 currency code")
+
+		if req.BasePrice != nil {
+			checkBasePrice(&eval, *req.BasePrice, *req.Currency)
+		}
+
+		if req.ReservePrice != nil && !req.RemovesReserve() {
+			checkReservePrice(&eval, *req.ReservePrice, *req.Currency)
+		}
+	}
+
+	if req.StartsAt != nil {
+		eval.CheckField(!req.StartsAt.IsZero(), "starts_at", "this field cannot be blank")
+	}
+
+	if req.AuctionEnd != nil {
+		eval.CheckField(!req.AuctionEnd.IsZero(), "auction_end", "this field cannot be blank")
+	}
+
+	if req.Tags != nil {
+		checkTags(&eval, *req.Tags)
+	}
+
+	if req.Quantity != nil {
+		checkQuantity(&eval, *req.Quantity)
+	}
+
+	if req.Pricing != nil {
+		checkPricing(&eval, *req.Pricing)
+	}
+
+	return eval
+}
+
+// RemovesReserve reports whether the update takes the reserve off, i.e.
+// reserve_price is zero.
+func (req UpdateProductReq) RemovesReserve() bool {
+	if req.ReservePrice == nil || req.Currency == nil {
+		return false
+	}
+
+	reservePrice, err := money.Parse(req.ReservePrice.String(), *req.Currency)
+	return err == nil && reservePrice.Amount == 0
+}
@@ -0,0 +1,23 @@
+package user
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (req ChangePasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.CurrentPassword), "current_password", "this field cannot be blank")
+
+	eval.CheckField(validator.MinChars(req.NewPassword, 8), "new_password", "password must be at least 8 characters")
+	eval.CheckField(req.NewPassword != req.CurrentPassword, "new_password", "must be different from the current password")
+
+	return eval
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+const (
+	DefaultTokenLifetimeDays = 30
+	MaxTokenLifetimeDays     = 365
+)
+
+var tokenScopes = []string{"read", "bid", "sell"}
+
+// CreateAccessTokenReq asks for a personal access token. Without
+// expires_in_days the token lasts DefaultTokenLifetimeDays.
+type CreateAccessTokenReq struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+func (req CreateAccessTokenReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Name, 100), "name", "this field must have at most 100 characters")
+
+	eval.CheckField(len(req.Scopes) > 0, "scopes", "at least one scope is required")
+	for i, scope := range req.Scopes {
+		eval.CheckField(slices.Contains(tokenScopes, scope), "scopes", "scopes must be read, bid or sell")
+		eval.CheckField(!slices.Contains(req.Scopes[:i], scope), "scopes", "scopes cannot repeat")
+	}
+
+	eval.CheckField(req.ExpiresInDays >= 0 && req.ExpiresInDays <= MaxTokenLifetimeDays, "expires_in_days", "must be between 1 and 365")
+
+	return eval
+}
+
+func (req CreateAccessTokenReq) Lifetime() int {
+	if req.ExpiresInDays == 0 {
+		return DefaultTokenLifetimeDays
+	}
+
+	return req.ExpiresInDays
+}
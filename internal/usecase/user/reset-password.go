@@ -0,0 +1,35 @@
+package user
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+type ForgotPasswordReq struct {
+	Email string `json:"email"`
+}
+
+func (req ForgotPasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Email), "email", "this field cannot be blank")
+	eval.CheckField(validator.Matches(req.Email, validator.EmailRX), "email", "must be a valid email")
+
+	return eval
+}
+
+type ResetPasswordReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (req ResetPasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Token), "token", "this field cannot be blank")
+
+	eval.CheckField(validator.MinChars(req.NewPassword, 8), "new_password", "password must be at least 8 characters")
+
+	return eval
+}
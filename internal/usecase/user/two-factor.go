@@ -0,0 +1,21 @@
+package user
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// TwoFactorCodeReq carries a code from the authenticator app or, where the
+// endpoint allows it, one of the recovery codes.
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+func (req TwoFactorCodeReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(req.Code), "code", "this field cannot be blank")
+
+	return eval
+}
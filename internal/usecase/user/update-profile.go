@@ -0,0 +1,34 @@
+package user
+
+import (
+	"context"
+
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// UpdateProfileReq is a partial update of the public part of a profile; the
+// email and password have their own flows.
+type UpdateProfileReq struct {
+	UserName *string `json:"user_name"`
+	Bio      *string `json:"bio"`
+}
+
+func (req UpdateProfileReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if req.UserName == nil && req.Bio == nil {
+		eval.AddFieldError("user", "at least one field must be changed")
+	}
+
+	if req.UserName != nil {
+		eval.CheckField(validator.NotBlank(*req.UserName), "user_name", "this field cannot be blank")
+		eval.CheckField(validator.MaxChars(*req.UserName, 50), "user_name", "this field must have at most 50 characters")
+	}
+
+	if req.Bio != nil {
+		eval.CheckField(validator.NotBlank(*req.Bio), "bio", "this field cannot be blank")
+		eval.CheckField((validator.MinChars(*req.Bio, 10) && validator.MaxChars(*req.Bio, 255)), "bio", "this field must have a length between 10 and 255 characters")
+	}
+
+	return eval
+}
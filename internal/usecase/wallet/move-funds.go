@@ -0,0 +1,35 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gregoryAlvim/gobid/internal/money"
+	"github.com/gregoryAlvim/gobid/internal/validator"
+)
+
+// MoveFundsReq is the body of deposits and withdrawals.
+type MoveFundsReq struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+func (req MoveFundsReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(money.IsSupportedCurrency(req.Currency), "currency", "must be a supported ISO 4217 currency code")
+
+	amount, err := money.Parse(req.Amount.String(), req.Currency)
+	switch {
+	case errors.Is(err, money.ErrTooManyDecimals):
+		eval.AddFieldError("amount", "this field has too many decimal places for the currency")
+	case errors.Is(err, money.ErrUnsupportedCurrency):
+	case err != nil:
+		eval.AddFieldError("amount", "this field must be a valid decimal amount")
+	default:
+		eval.CheckField(amount.IsPositive(), "amount", "this field must be greater than zero")
+	}
+
+	return eval
+}
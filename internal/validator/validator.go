@@ -46,3 +46,14 @@ func MinChars(value string, min int) bool {
 func Matches(value string, rx *regexp.Regexp) bool {
 	return rx.MatchString(value)
 }
+
+func NotContainsAny(value string, words []string) bool {
+	lower := strings.ToLower(value)
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" && strings.Contains(lower, word) {
+			return false
+		}
+	}
+
+	return true
+}